@@ -4,6 +4,7 @@ import (
 	"log"
 	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/mattieserver/netbox-oxidized-sync/internal/confighelper"
 	"github.com/mattieserver/netbox-oxidized-sync/internal/configparser"
@@ -12,7 +13,7 @@ import (
 	"github.com/mattieserver/netbox-oxidized-sync/internal/netboxparser"
 )
 
-func worker(id int, jobs <-chan httphelper.OxidizedNode, results chan<- int, netboxdevices *[]model.NetboxDevice, oxidizedhttp *httphelper.OxidizedHTTPClient, netboxhttp *httphelper.NetboxHTTPClient) {
+func worker(id int, jobs <-chan httphelper.OxidizedNode, results chan<- int, netboxdevices *[]model.NetboxDevice, oxidizedhttp *httphelper.OxidizedHTTPClient, netboxhttp *httphelper.NetboxHTTPClient, conf *confighelper.Config) {
 	for j := range jobs {
 		log.Printf("Got oxided device: '%s' on worker %s", j.Name, strconv.Itoa(id))
 
@@ -24,19 +25,84 @@ func worker(id int, jobs <-chan httphelper.OxidizedNode, results chan<- int, net
 			config := oxidizedhttp.GetNodeConfig(j.FullName)
 
 			switch j.Model {
-			case "IOS":
-				log.Println("IOS not supported for now")
+			case "IOS", "IOSXE":
+				log.Printf("Device: '%s' has IOS", j.Name)
+				iosInterfaces, _ := configparser.ParseIOSConfig(&config)
+				syncInterfaces(iosInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "JunOS":
+				log.Printf("Device: '%s' has JunOS", j.Name)
+				junosInterfaces, _ := configparser.ParseJunosConfig(&config)
+				syncInterfaces(junosInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "EOS":
+				log.Printf("Device: '%s' has EOS", j.Name)
+				eosInterfaces, _ := configparser.ParseEOSConfig(&config)
+				syncInterfaces(eosInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "NXOS":
+				log.Printf("Device: '%s' has NX-OS", j.Name)
+				nxosInterfaces, _ := configparser.ParseNXOSConfig(&config)
+				syncInterfaces(nxosInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "AOSCX":
+				log.Printf("Device: '%s' has AOS-CX", j.Name)
+				aoscxInterfaces, _ := configparser.ParseAOSCXConfig(&config)
+				syncInterfaces(aoscxInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "Procurve":
+				log.Printf("Device: '%s' has ArubaOS-Switch", j.Name)
+				procurveInterfaces, _ := configparser.ParseProcurveConfig(&config)
+				syncInterfaces(procurveInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "RouterOS":
+				log.Printf("Device: '%s' has RouterOS", j.Name)
+				routerosInterfaces, _ := configparser.ParseRouterOSConfig(&config)
+				syncInterfaces(routerosInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "PanOS":
+				log.Printf("Device: '%s' has PAN-OS", j.Name)
+				panosInterfaces, err := configparser.ParsePanOSConfig(&config)
+				if err != nil {
+					log.Printf("Device: '%s' config could not be parsed: %s", j.Name, err)
+					break
+				}
+				syncInterfaces(panosInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "ASA", "FTD":
+				log.Printf("Device: '%s' has ASA", j.Name)
+				asaInterfaces, _ := configparser.ParseASAConfig(&config)
+				syncInterfaces(asaInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "VRP":
+				log.Printf("Device: '%s' has Huawei VRP", j.Name)
+				vrpInterfaces, _ := configparser.ParseVRPConfig(&config)
+				syncInterfaces(vrpInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "Comware":
+				log.Printf("Device: '%s' has Comware", j.Name)
+				comwareInterfaces, _ := configparser.ParseComwareConfig(&config)
+				syncInterfaces(comwareInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "OpnSense", "PfSense":
+				log.Printf("Device: '%s' has %s", j.Name, j.Model)
+				opnsenseInterfaces, err := configparser.ParseOPNsenseConfig(&config)
+				if err != nil {
+					log.Printf("Device: '%s' config could not be parsed: %s", j.Name, err)
+					break
+				}
+				syncInterfaces(opnsenseInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "VyOS", "EdgeOS":
+				log.Printf("Device: '%s' has %s", j.Name, j.Model)
+				vyosInterfaces, _ := configparser.ParseVyOSConfig(&config)
+				syncInterfaces(vyosInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "Cumulus":
+				log.Printf("Device: '%s' has Cumulus", j.Name)
+				cumulusInterfaces, _ := configparser.ParseCumulusConfig(&config)
+				syncInterfaces(cumulusInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+			case "SONiC":
+				log.Printf("Device: '%s' has SONiC", j.Name)
+				sonicInterfaces, err := configparser.ParseSONiCConfig(&config)
+				if err != nil {
+					log.Printf("Device: '%s' config could not be parsed: %s", j.Name, err)
+					break
+				}
+				syncInterfaces(sonicInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
 			case "FortiOS":
 				log.Printf("Device: '%s' has fortiOS", j.Name)
 				fortigateInterfaces, _ := configparser.ParseFortiOSConfig(&config)
-				var netboxDevice = (*netboxdevices)[idx]
-				netboxInterfaceForDevice := netboxhttp.GetIntefacesForDevice(strconv.Itoa(netboxDevice.ID))
-				netboxVlansForSite, err := netboxhttp.GetVlansForSite(strconv.Itoa(netboxDevice.Site.ID))
-				if err != nil {
-					continue
-				}
-				interfacesToUpdate := netboxparser.ParseFortigateInterfaces(fortigateInterfaces, &netboxInterfaceForDevice, strconv.Itoa(netboxDevice.ID))
-				netboxhttp.UpdateOrCreateInferface(&interfacesToUpdate, &netboxVlansForSite, netboxDevice.Site.ID, netboxDevice.Tenant.ID)
+				syncInterfaces(fortigateInterfaces, (*netboxdevices)[idx], j, conf, netboxhttp)
+				managedSwitches, _ := configparser.ParseFortiSwitchConfig(&config)
+				syncManagedSwitches(managedSwitches, netboxdevices, conf, netboxhttp)
 
 			default:
 				log.Printf("Model '%s' currently not supported", j.Model)
@@ -47,7 +113,132 @@ func worker(id int, jobs <-chan httphelper.OxidizedNode, results chan<- int, net
 	}
 }
 
-func loadOxidizedDevices(oxidizedhttp *httphelper.OxidizedHTTPClient, netboxhttp *httphelper.NetboxHTTPClient) {
+// syncPorts syncs the interfaces of the device and their vlans, it returns the vlans of
+// the site and the vrfs for the rest of the sync.
+func syncPorts(deviceInterfaces *[]model.FortigateInterface, netboxDevice model.NetboxDevice, node httphelper.OxidizedNode, conf *confighelper.Config, netboxhttp *httphelper.NetboxHTTPClient) ([]model.NetboxVlan, []model.NetboxVrf, error) {
+	netboxInterfaceForDevice := netboxhttp.GetIntefacesForDevice(strconv.Itoa(netboxDevice.ID))
+	netboxVlansForSite, err := netboxhttp.GetVlansForSite(strconv.Itoa(netboxDevice.Site.ID))
+	if err != nil {
+		return nil, nil, err
+	}
+	netboxVrfs, err := netboxhttp.GetAllVrfs()
+	if err != nil {
+		return nil, nil, err
+	}
+	netboxVdcs, err := netboxhttp.GetVdcsForDevice(strconv.Itoa(netboxDevice.ID))
+	if err != nil {
+		return nil, nil, err
+	}
+	for index := range *deviceInterfaces {
+		(*deviceInterfaces)[index].Vrf = netboxhttp.VrfName((*deviceInterfaces)[index].Vrf, netboxDevice)
+	}
+	interfacesToUpdate := netboxparser.ParseFortigateInterfaces(deviceInterfaces, &netboxInterfaceForDevice, strconv.Itoa(netboxDevice.ID), node.Model, conf.Netbox.InterfaceTypes[node.Model])
+	netboxhttp.UpdateOrCreateInferface(&interfacesToUpdate, &netboxVlansForSite, &netboxVrfs, &netboxVdcs, netboxDevice.Site.ID, netboxDevice.Tenant.ID)
+	return netboxVlansForSite, netboxVrfs, nil
+}
+
+func syncInterfaces(deviceInterfaces *[]model.FortigateInterface, netboxDevice model.NetboxDevice, node httphelper.OxidizedNode, conf *confighelper.Config, netboxhttp *httphelper.NetboxHTTPClient) {
+	netboxVlansForSite, netboxVrfs, err := syncPorts(deviceInterfaces, netboxDevice, node, conf, netboxhttp)
+	if err != nil {
+		return
+	}
+
+	for _, deviceInterface := range *deviceInterfaces {
+		if len(deviceInterface.VniMappings) != 0 {
+			netboxhttp.SyncVxlanMappings(deviceInterface.VniMappings, &netboxVlansForSite, netboxDevice.Site.ID, netboxDevice.Tenant.ID)
+		}
+	}
+
+	netboxPrefixes, err := netboxhttp.GetPrefixesForSite(strconv.Itoa(netboxDevice.Site.ID))
+	if err == nil {
+		prefixesToUpdate := netboxparser.ParsePrefixes(deviceInterfaces, &netboxPrefixes, netboxhttp.ManagedTagID(), netboxDevice.Site.ID, netboxDevice.Tenant.ID)
+		netboxhttp.UpdateOrCreatePrefixes(&prefixesToUpdate, &netboxVlansForSite, &netboxVrfs, netboxDevice.Site.ID, netboxDevice.Tenant.ID)
+	}
+
+	// the interfaces are fetched again so the ip addresses can be assigned to the created interfaces
+	netboxInterfaceForDevice := netboxhttp.GetIntefacesForDevice(strconv.Itoa(netboxDevice.ID))
+	netboxIPAddresses, err := netboxhttp.GetIPAddressesForDevice(strconv.Itoa(netboxDevice.ID))
+	if err != nil {
+		return
+	}
+	ipAddressesToUpdate := netboxparser.ParseIPAddresses(deviceInterfaces, &netboxInterfaceForDevice, &netboxIPAddresses, netboxhttp.ManagedTagID())
+	netboxhttp.UpdateOrCreateIPAddresses(&ipAddressesToUpdate, &netboxVrfs, netboxDevice.Tenant.ID)
+
+	netboxFhrpGroups, err := netboxhttp.GetAllFhrpGroups()
+	if err == nil {
+		netboxFhrpAssignments, err := netboxhttp.GetFhrpGroupAssignmentsForDevice(strconv.Itoa(netboxDevice.ID))
+		if err == nil {
+			fhrpGroupsToUpdate := netboxparser.ParseFhrpGroups(deviceInterfaces, &netboxInterfaceForDevice, &netboxFhrpGroups, &netboxFhrpAssignments)
+			netboxhttp.UpdateOrCreateFhrpGroups(&fhrpGroupsToUpdate, &netboxVrfs, netboxDevice.Tenant.ID)
+		}
+	}
+
+	// the ip addresses are fetched again to get the ids of the created addresses
+	netboxIPAddresses, err = netboxhttp.GetIPAddressesForDevice(strconv.Itoa(netboxDevice.ID))
+	if err != nil {
+		return
+	}
+
+	tunnelInterfaceIds := netboxparser.TunnelInterfaceIds(deviceInterfaces, &netboxInterfaceForDevice)
+	if len(tunnelInterfaceIds) > 0 {
+		netboxTunnels, err := netboxhttp.GetAllTunnels()
+		if err == nil {
+			// the remote gateways are looked up to also terminate the tunnels on the peer
+			netboxRemoteIPAddresses, err := netboxhttp.GetIPAddresses(netboxparser.TunnelRemoteGateways(deviceInterfaces))
+			if err == nil {
+				terminationInterfaceIds := append(tunnelInterfaceIds, netboxparser.TunnelPeerInterfaceIds(&netboxRemoteIPAddresses)...)
+				netboxTerminations, err := netboxhttp.GetTerminationsForInterfaces(terminationInterfaceIds)
+				if err == nil {
+					tunnelsToUpdate := netboxparser.ParseTunnels(deviceInterfaces, netboxDevice.Name, &netboxInterfaceForDevice, &netboxIPAddresses, &netboxRemoteIPAddresses, &netboxTunnels, &netboxTerminations)
+					netboxhttp.UpdateOrCreateTunnels(&tunnelsToUpdate, netboxDevice.Tenant.ID)
+				}
+			}
+		}
+	}
+
+	syncPrimaryIP(deviceInterfaces, netboxDevice, node, &conf.Netbox.PrimaryIP, &netboxIPAddresses, netboxhttp)
+}
+
+// syncPrimaryIP sets the primary ip of the device to the oxidized node ip, or to the
+// address of the management interface configured for the model of the device.
+func syncPrimaryIP(deviceInterfaces *[]model.FortigateInterface, netboxDevice model.NetboxDevice, node httphelper.OxidizedNode, primaryIP *confighelper.PrimaryIP, netboxIPAddresses *[]model.NetboxIPAddress, netboxhttp *httphelper.NetboxHTTPClient) {
+	var managementIP, managementInterface string
+	switch primaryIP.Mode {
+	case "oxidized":
+		managementIP = node.IP
+	case "interface":
+		managementInterface = primaryIP.Interfaces[node.Model]
+		if managementInterface == "" {
+			return
+		}
+	default:
+		return
+	}
+
+	primaryIP4, primaryIP6 := netboxparser.ParsePrimaryIPs(deviceInterfaces, netboxDevice, netboxIPAddresses, managementIP, managementInterface)
+	netboxhttp.UpdatePrimaryIPs(netboxDevice, primaryIP4, primaryIP6)
+}
+
+// syncManagedSwitches syncs the ports and vlans of the FortiSwitches managed by a
+// FortiGate, the switches are matched on serial or name as they are not in Oxidized.
+// The switch-id of a FortiSwitch is its serial, switches without a serial in Netbox are
+// often named after it so the serial is compared with the name as well.
+func syncManagedSwitches(managedSwitches *[]model.ManagedSwitch, netboxdevices *[]model.NetboxDevice, conf *confighelper.Config, netboxhttp *httphelper.NetboxHTTPClient) {
+	for _, managedSwitch := range *managedSwitches {
+		idx := slices.IndexFunc(*netboxdevices, func(c model.NetboxDevice) bool {
+			return strings.EqualFold(c.Serial, managedSwitch.Serial) || strings.EqualFold(c.Name, managedSwitch.Serial) || (managedSwitch.Name != "" && c.Name == managedSwitch.Name)
+		})
+		if idx == -1 {
+			log.Printf("FortiSwitch: '%s' not found in netbox", managedSwitch.Serial)
+			continue
+		}
+		log.Printf("FortiSwitch: '%s' found in netbox as '%s'", managedSwitch.Serial, (*netboxdevices)[idx].Name)
+		// the switch ports have no ip addresses, only the ports and their vlans are synced
+		syncPorts(&managedSwitch.Interfaces, (*netboxdevices)[idx], httphelper.OxidizedNode{Name: (*netboxdevices)[idx].Name, Model: "FortiSwitch"}, conf, netboxhttp)
+	}
+}
+
+func loadOxidizedDevices(oxidizedhttp *httphelper.OxidizedHTTPClient, netboxhttp *httphelper.NetboxHTTPClient, conf *confighelper.Config) {
 	log.Println("Starting to get all Oxidized Devices")
 	nodes := oxidizedhttp.GetAllNodes()
 	log.Println("Got all Oxidized Devices")
@@ -60,7 +251,7 @@ func loadOxidizedDevices(oxidizedhttp *httphelper.OxidizedHTTPClient, netboxhttp
 	results := make(chan int, len(nodes))
 
 	for w := 1; w <= 3; w++ {
-		go worker(w, jobs, results, &devices, oxidizedhttp, netboxhttp)
+		go worker(w, jobs, results, &devices, oxidizedhttp, netboxhttp, conf)
 	}
 
 	for _, element := range nodes {
@@ -85,6 +276,7 @@ func main() {
 	oxidizedhttp := httphelper.NewOxidized(conf.Oxidized.BaseURL, conf.Oxidized.Username, conf.Oxidized.Password)
 
 	netboxhttp.GetManagedTag(conf.Netbox.TagName)
+	netboxhttp.SetVrfOptions(conf.Netbox.VrfNameFormat, conf.Netbox.VrfTenant)
 
-	loadOxidizedDevices(&oxidizedhttp, &netboxhttp)
+	loadOxidizedDevices(&oxidizedhttp, &netboxhttp, &conf)
 }
@@ -6,12 +6,21 @@ import (
 	"encoding/json"
 )
 
+type PrimaryIP struct {
+	Mode       string            `json:"mode"`
+	Interfaces map[string]string `json:"interfaces"`
+}
+
 type Config struct {
 	Netbox struct {
 		BaseURL string `json:"base_url"`
 		APIKey  string `json:"api_key"`
 		Roles	string `json:"roles"`
 		TagName string `json:"tag-name"`
+		VrfNameFormat string `json:"vrf-name-format"`
+		VrfTenant string `json:"vrf-tenant"`
+		PrimaryIP PrimaryIP `json:"primary-ip"`
+		InterfaceTypes map[string]map[string]string `json:"interface-types"`
 	} `json:"netbox"`
 	Oxidized struct {
 		BaseURL  string `json:"base_url"`
@@ -0,0 +1,111 @@
+package configparser
+
+import (
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	aoscxLag              = "lag "
+	aoscxVlanAccess       = "vlan access "
+	aoscxVlanTrunkNative  = "vlan trunk native "
+	aoscxVlanTrunkAllowed = "vlan trunk allowed "
+	aoscxVrfAttach        = "vrf attach "
+	aoscxIPAddress        = "ip address "
+	aoscxIPv6Address      = "ipv6 address "
+)
+
+func ParseAOSCXConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+	lagMembers := map[string][]string{}
+
+	for _, section := range parseIndentedSections(config, iosInterfacePrefix) {
+		iface, lag := parseAOSCXInterface(section)
+		if iface.Name == "" {
+			continue
+		}
+		if lag != "" {
+			lagMembers[lag] = append(lagMembers[lag], iface.Name)
+		}
+		deviceInterfaces = append(deviceInterfaces, iface)
+	}
+
+	addLagMembers(&deviceInterfaces, lagMembers)
+
+	return &deviceInterfaces, nil
+}
+
+func parseAOSCXInterface(section configSection) (model.FortigateInterface, string) {
+	var iface model.FortigateInterface
+	var lag, accessVlan, nativeVlan string
+	var allowedVlans []string
+	allowAll := false
+	trunk := false
+
+	// "lag 1", "vlan 10" and "loopback 0" are shown as "lag1", "vlan10" and "loopback0" by the switch
+	iface.Name = strings.ReplaceAll(section.header, " ", "")
+
+	for _, line := range section.lines {
+		switch {
+		case strings.HasPrefix(line, iosDescription):
+			iface.Description = strings.TrimPrefix(line, iosDescription)
+		case line == iosShutdown:
+			iface.Status = "down"
+		case strings.HasPrefix(line, aoscxLag):
+			lag = "lag" + strings.TrimPrefix(line, aoscxLag)
+		case strings.HasPrefix(line, aoscxVlanAccess):
+			accessVlan = strings.TrimPrefix(line, aoscxVlanAccess)
+		case strings.HasPrefix(line, aoscxVlanTrunkNative):
+			trunk = true
+			nativeVlan = strings.Fields(strings.TrimPrefix(line, aoscxVlanTrunkNative))[0]
+		case strings.HasPrefix(line, aoscxVlanTrunkAllowed):
+			trunk = true
+			// AOS-CX repeats the line when the allowed list is long, every line adds vlans
+			allowed := strings.TrimPrefix(line, aoscxVlanTrunkAllowed)
+			if allowed == "all" {
+				allowAll = true
+			} else {
+				allowedVlans, _ = applyVlanListCommand(allowedVlans, "add "+allowed)
+			}
+		case strings.HasPrefix(line, aoscxVrfAttach):
+			iface.Vrf = strings.TrimPrefix(line, aoscxVrfAttach)
+		case strings.HasPrefix(line, aoscxIPAddress), strings.HasPrefix(line, aoscxIPv6Address):
+			addressFields := strings.Fields(line)
+			if len(addressFields) > 2 && strings.Contains(addressFields[2], "/") {
+				iface.IPAddresses = append(iface.IPAddresses, addressFields[2])
+			}
+		}
+	}
+
+	// a trunk without an allowed list carries every vlan
+	if trunk && allowedVlans == nil {
+		allowAll = true
+	}
+
+	switch {
+	case trunk:
+		iface.VlanMode = trunkVlanMode(allowAll)
+		iface.VlanId = nativeVlan
+		if !allowAll {
+			iface.TaggedVlans = allowedVlans
+		}
+	case accessVlan != "":
+		iface.VlanMode = vlanModeAccess
+		iface.VlanId = accessVlan
+	}
+
+	switch {
+	case strings.HasPrefix(iface.Name, "lag"):
+		iface.InterfaceType = "aggregate"
+	case strings.HasPrefix(iface.Name, "vlan"):
+		iface.InterfaceType = "vlan"
+		iface.VlanId = strings.TrimPrefix(iface.Name, "vlan")
+	case strings.HasPrefix(iface.Name, "loopback"), strings.HasPrefix(iface.Name, "tunnel"), strings.HasPrefix(iface.Name, "vxlan"):
+		iface.InterfaceType = "virtual"
+	default:
+		iface.InterfaceType = "physical"
+	}
+
+	return iface, lag
+}
@@ -0,0 +1,87 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+)
+
+const aoscxFixture = `!Version ArubaOS-CX FL.10.10.1020
+hostname sw1
+vlan 1,10,20,30
+interface lag 1
+    description uplink
+    no shutdown
+    vlan trunk native 1
+    vlan trunk allowed 10,20
+    vlan trunk allowed 30
+    lacp mode active
+interface 1/1/1
+    no shutdown
+    lag 1
+interface 1/1/2
+    no shutdown
+    lag 1
+interface 1/1/3
+    no shutdown
+    vlan access 10
+interface 1/1/4
+    shutdown
+    vlan trunk native 1
+interface 1/1/5
+    no shutdown
+    vrf attach CUST
+    ip address 192.0.2.0/31
+interface loopback 0
+    ip address 10.255.0.1/32
+interface vlan 10
+    vrf attach CUST
+    ip address 10.0.10.1/24
+    ipv6 address 2001:db8:10::1/64
+`
+
+func TestParseAOSCXConfig(t *testing.T) {
+	config := aoscxFixture
+	interfaces, err := ParseAOSCXConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		interfaceType string
+		status        string
+		vlanMode      string
+		vlanId        string
+		taggedVlans   []string
+		members       []string
+		vrf           string
+		ipAddresses   []string
+	}{
+		{"lag1", "aggregate", "", vlanModeTagged, "1", []string{"10", "20", "30"}, []string{"1/1/1", "1/1/2"}, "", nil},
+		{"1/1/1", "physical", "", "", "", nil, nil, "", nil},
+		{"1/1/3", "physical", "", vlanModeAccess, "10", nil, nil, "", nil},
+		{"1/1/4", "physical", "down", vlanModeTaggedAll, "1", nil, nil, "", nil},
+		{"1/1/5", "physical", "", "", "", nil, nil, "CUST", []string{"192.0.2.0/31"}},
+		{"loopback0", "virtual", "", "", "", nil, nil, "", []string{"10.255.0.1/32"}},
+		{"vlan10", "vlan", "", "", "10", nil, nil, "CUST", []string{"10.0.10.1/24", "2001:db8:10::1/64"}},
+	}
+	for _, tt := range tests {
+		iface := findInterface(t, interfaces, tt.name)
+		if iface.InterfaceType != tt.interfaceType || iface.Status != tt.status {
+			t.Errorf("%s: got type %q status %q, want %q %q", tt.name, iface.InterfaceType, iface.Status, tt.interfaceType, tt.status)
+		}
+		if iface.VlanMode != tt.vlanMode || iface.VlanId != tt.vlanId || !slices.Equal(iface.TaggedVlans, tt.taggedVlans) {
+			t.Errorf("%s: got mode %q vlan %q tagged %v, want %q %q %v", tt.name, iface.VlanMode, iface.VlanId, iface.TaggedVlans, tt.vlanMode, tt.vlanId, tt.taggedVlans)
+		}
+		if !slices.Equal(iface.Members, tt.members) {
+			t.Errorf("%s: got members %v, want %v", tt.name, iface.Members, tt.members)
+		}
+		if iface.Vrf != tt.vrf || !slices.Equal(iface.IPAddresses, tt.ipAddresses) {
+			t.Errorf("%s: got vrf %q addresses %v, want %q %v", tt.name, iface.Vrf, iface.IPAddresses, tt.vrf, tt.ipAddresses)
+		}
+	}
+
+	if len(*interfaces) != 8 {
+		t.Errorf("got %d interfaces, want 8", len(*interfaces))
+	}
+}
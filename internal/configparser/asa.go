@@ -0,0 +1,118 @@
+package configparser
+
+import (
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	asaNameif          = "nameif "
+	asaSecurityLevel   = "security-level "
+	asaVlan            = "vlan "
+	asaManagementOnly  = "management-only"
+	asaIPAddress       = "ip address "
+	asaIPv6Address     = "ipv6 address "
+	asaMemberInterface = "member-interface "
+	asaRedundantName   = "Redundant"
+)
+
+func ParseASAConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+	lagMembers := map[string][]string{}
+
+	for _, section := range parseIndentedSections(config, iosInterfacePrefix) {
+		iface, channelGroup := parseASAInterface(section)
+		if iface.Name == "" {
+			continue
+		}
+		if channelGroup != "" {
+			lagName := iosPortChannelName + channelGroup
+			lagMembers[lagName] = append(lagMembers[lagName], iface.Name)
+		}
+		deviceInterfaces = append(deviceInterfaces, iface)
+	}
+
+	addLagMembers(&deviceInterfaces, lagMembers)
+
+	return &deviceInterfaces, nil
+}
+
+func parseASAInterface(section configSection) (model.FortigateInterface, string) {
+	var iface model.FortigateInterface
+	var channelGroup, securityLevel, description string
+	mgmtOnly := false
+
+	fields := strings.Fields(section.header)
+	if len(fields) == 0 {
+		return iface, ""
+	}
+	iface.Name = fields[0]
+
+	for _, line := range section.lines {
+		switch {
+		case strings.HasPrefix(line, iosDescription):
+			description = strings.TrimPrefix(line, iosDescription)
+		case line == iosShutdown:
+			iface.Status = "down"
+		case strings.HasPrefix(line, asaNameif):
+			iface.Label = strings.TrimPrefix(line, asaNameif)
+		case strings.HasPrefix(line, asaSecurityLevel):
+			securityLevel = strings.TrimPrefix(line, asaSecurityLevel)
+		case strings.HasPrefix(line, asaVlan):
+			iface.VlanId = strings.TrimPrefix(line, asaVlan)
+		case line == asaManagementOnly:
+			mgmtOnly = true
+		case strings.HasPrefix(line, iosChannelGroup):
+			channelGroup = strings.Fields(strings.TrimPrefix(line, iosChannelGroup))[0]
+		case strings.HasPrefix(line, asaMemberInterface):
+			iface.Members = append(iface.Members, strings.TrimPrefix(line, asaMemberInterface))
+		case strings.HasPrefix(line, asaIPAddress):
+			// ip address 10.0.0.1 255.255.255.0 standby 10.0.0.2
+			addressFields := strings.Fields(strings.TrimPrefix(line, asaIPAddress))
+			if len(addressFields) < 2 {
+				continue
+			}
+			if address := maskToCIDR(addressFields[0], addressFields[1]); address != "" {
+				iface.IPAddresses = append(iface.IPAddresses, address)
+				if len(addressFields) == 4 && addressFields[2] == "standby" {
+					_, prefixLength, _ := strings.Cut(address, "/")
+					iface.StandbyIPs = append(iface.StandbyIPs, addressFields[3]+"/"+prefixLength)
+				}
+			}
+		case strings.HasPrefix(line, asaIPv6Address):
+			// ipv6 address 2001:db8::1/64 standby 2001:db8::2
+			addressFields := strings.Fields(strings.TrimPrefix(line, asaIPv6Address))
+			if len(addressFields) == 0 || !strings.Contains(addressFields[0], "/") {
+				continue
+			}
+			iface.IPAddresses = append(iface.IPAddresses, addressFields[0])
+			if len(addressFields) == 3 && addressFields[1] == "standby" {
+				_, prefixLength, _ := strings.Cut(addressFields[0], "/")
+				iface.StandbyIPs = append(iface.StandbyIPs, addressFields[2]+"/"+prefixLength)
+			}
+		}
+	}
+	iface.MgmtOnly = &mgmtOnly
+
+	iface.Description = description
+	if securityLevel != "" {
+		createDescriptionBuilder(securityLevel, "security-level", &iface.Description)
+	}
+
+	baseName, _, isSubinterface := strings.Cut(iface.Name, ".")
+
+	switch {
+	case isSubinterface:
+		iface.InterfaceType = "vlan"
+		iface.Parent = baseName
+	case strings.HasPrefix(iface.Name, iosPortChannelName), strings.HasPrefix(iface.Name, asaRedundantName):
+		iface.InterfaceType = "aggregate"
+	case strings.HasPrefix(iface.Name, "Tunnel"), strings.HasPrefix(iface.Name, "BVI"):
+		iface.InterfaceType = "virtual"
+	default:
+		iface.InterfaceType = "physical"
+	}
+
+	return iface, channelGroup
+}
@@ -0,0 +1,118 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+)
+
+const asaFixture = `: Saved
+:
+: Serial Number: JAD000000AA
+: Hardware:   ASA5516, 8192 MB RAM, CPU Atom C2000 series 2416 MHz, 1 CPU (8 cores)
+:
+ASA Version 9.16(4)
+!
+hostname fw1
+!
+interface GigabitEthernet1/1
+ channel-group 1 mode active
+ no nameif
+ no security-level
+ no ip address
+!
+interface GigabitEthernet1/2
+ channel-group 1 mode active
+ no nameif
+ no security-level
+ no ip address
+!
+interface GigabitEthernet1/3
+ shutdown
+ no nameif
+ no security-level
+ no ip address
+!
+interface Port-channel1
+ lacp max-bundle 8
+ no nameif
+ no security-level
+ no ip address
+!
+interface Port-channel1.100
+ description servers
+ vlan 100
+ nameif inside
+ security-level 100
+ ip address 10.0.100.1 255.255.255.0 standby 10.0.100.2
+ ipv6 address 2001:db8:100::1/64 standby 2001:db8:100::2
+!
+interface Redundant1
+ member-interface GigabitEthernet1/4
+ member-interface GigabitEthernet1/5
+ nameif outside
+ security-level 0
+ ip address 192.0.2.2 255.255.255.0
+!
+interface Management1/1
+ management-only
+ nameif management
+ security-level 100
+ ip address 172.16.0.10 255.255.255.0
+!
+interface Tunnel1
+ nameif vpn-branch
+ ip address 169.254.0.1 255.255.255.252
+ tunnel source interface outside
+!
+`
+
+func TestParseASAConfig(t *testing.T) {
+	config := asaFixture
+	interfaces, err := ParseASAConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		interfaceType string
+		status        string
+		description   string
+		label         string
+		parent        string
+		vlanId        string
+		members       []string
+		mgmtOnly      bool
+		ipAddresses   []string
+		standbyIPs    []string
+	}{
+		{"GigabitEthernet1/1", "physical", "", "", "", "", "", nil, false, nil, nil},
+		{"GigabitEthernet1/3", "physical", "down", "", "", "", "", nil, false, nil, nil},
+		{"Port-channel1", "aggregate", "", "", "", "", "", []string{"GigabitEthernet1/1", "GigabitEthernet1/2"}, false, nil, nil},
+		{"Port-channel1.100", "vlan", "", "servers; security-level: 100", "inside", "Port-channel1", "100", nil, false,
+			[]string{"10.0.100.1/24", "2001:db8:100::1/64"}, []string{"10.0.100.2/24", "2001:db8:100::2/64"}},
+		{"Redundant1", "aggregate", "", "security-level: 0", "outside", "", "", []string{"GigabitEthernet1/4", "GigabitEthernet1/5"}, false, []string{"192.0.2.2/24"}, nil},
+		{"Management1/1", "physical", "", "security-level: 100", "management", "", "", nil, true, []string{"172.16.0.10/24"}, nil},
+		{"Tunnel1", "virtual", "", "", "vpn-branch", "", "", nil, false, []string{"169.254.0.1/30"}, nil},
+	}
+	for _, tt := range tests {
+		iface := findInterface(t, interfaces, tt.name)
+		if iface.InterfaceType != tt.interfaceType || iface.Status != tt.status || iface.Description != tt.description || iface.Parent != tt.parent {
+			t.Errorf("%s: got type %q status %q description %q parent %q, want %q %q %q %q", tt.name,
+				iface.InterfaceType, iface.Status, iface.Description, iface.Parent, tt.interfaceType, tt.status, tt.description, tt.parent)
+		}
+		if iface.Label != tt.label || iface.VlanId != tt.vlanId || iface.MgmtOnly == nil || *iface.MgmtOnly != tt.mgmtOnly {
+			t.Errorf("%s: got label %q vlan %q mgmt-only %v, want %q %q %v", tt.name, iface.Label, iface.VlanId, iface.MgmtOnly, tt.label, tt.vlanId, tt.mgmtOnly)
+		}
+		if !slices.Equal(iface.Members, tt.members) {
+			t.Errorf("%s: got members %v, want %v", tt.name, iface.Members, tt.members)
+		}
+		if !slices.Equal(iface.IPAddresses, tt.ipAddresses) || !slices.Equal(iface.StandbyIPs, tt.standbyIPs) {
+			t.Errorf("%s: got addresses %v standby %v, want %v %v", tt.name, iface.IPAddresses, iface.StandbyIPs, tt.ipAddresses, tt.standbyIPs)
+		}
+	}
+
+	if len(*interfaces) != 8 {
+		t.Errorf("got %d interfaces, want 8", len(*interfaces))
+	}
+}
@@ -0,0 +1,221 @@
+package configparser
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	vlanModeAccess    = "access"
+	vlanModeTagged    = "tagged"
+	vlanModeTaggedAll = "tagged-all"
+
+	fhrpHsrp  = "hsrp"
+	fhrpVrrp2 = "vrrp2"
+	fhrpVrrp3 = "vrrp3"
+)
+
+type configSection struct {
+	header string
+	lines  []string
+}
+
+// parseIndentedSections returns every top-level block starting with keyword
+// together with its indented child lines, as used by IOS style configs.
+func parseIndentedSections(config *string, keyword string) []configSection {
+	var (
+		sections []configSection
+		tracking bool
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(*config))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if line == "" {
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			if tracking {
+				sections[len(sections)-1].lines = append(sections[len(sections)-1].lines, strings.TrimSpace(line))
+			}
+			continue
+		}
+
+		tracking = false
+		if strings.HasPrefix(line, keyword) {
+			tracking = true
+			sections = append(sections, configSection{header: strings.TrimSpace(strings.TrimPrefix(line, keyword))})
+		}
+	}
+
+	return sections
+}
+
+// expandVlanList turns a list like "10,20-22 30" into the separate vlan ids.
+func expandVlanList(list string) []string {
+	var result []string
+
+	fields := strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' })
+	for _, field := range fields {
+		start, end, isRange := strings.Cut(field, "-")
+		if !isRange {
+			if _, err := strconv.Atoi(field); err == nil {
+				result = append(result, field)
+			}
+			continue
+		}
+
+		first, err := strconv.Atoi(start)
+		if err != nil {
+			continue
+		}
+		last, err := strconv.Atoi(end)
+		if err != nil {
+			continue
+		}
+		for vid := first; vid <= last; vid++ {
+			result = append(result, strconv.Itoa(vid))
+		}
+	}
+
+	return result
+}
+
+// applyVlanListCommand applies an "allowed vlan" style argument (plain list, add,
+// remove, except, all or none) to the current vlan list. The returned bool is
+// true when all vlans are allowed.
+func applyVlanListCommand(current []string, argument string) ([]string, bool) {
+	action, list, _ := strings.Cut(strings.TrimSpace(argument), " ")
+
+	switch action {
+	case "all":
+		return nil, true
+	case "none":
+		return []string{}, false
+	case "add":
+		for _, vid := range expandVlanList(list) {
+			if !slices.Contains(current, vid) {
+				current = append(current, vid)
+			}
+		}
+		return current, false
+	case "remove":
+		removed := expandVlanList(list)
+		return slices.DeleteFunc(current, func(vid string) bool { return slices.Contains(removed, vid) }), false
+	case "except":
+		except := expandVlanList(list)
+		var result []string
+		for vid := 1; vid <= 4094; vid++ {
+			if !slices.Contains(except, strconv.Itoa(vid)) {
+				result = append(result, strconv.Itoa(vid))
+			}
+		}
+		return result, false
+	}
+
+	vlans := expandVlanList(argument)
+	if len(vlans) == 4094 {
+		return nil, true
+	}
+	return vlans, false
+}
+
+func trunkVlanMode(allVlans bool) string {
+	if allVlans {
+		return vlanModeTaggedAll
+	}
+	return vlanModeTagged
+}
+
+// addLagMembers sets the members on the aggregate interfaces, creating the
+// aggregate when the config only references it from its members.
+func addLagMembers(deviceInterfaces *[]model.FortigateInterface, lagMembers map[string][]string) {
+	lagNames := make([]string, 0, len(lagMembers))
+	for lagName := range lagMembers {
+		lagNames = append(lagNames, lagName)
+	}
+	sort.Strings(lagNames)
+
+	for _, lagName := range lagNames {
+		members := lagMembers[lagName]
+		found := false
+		for index, iface := range *deviceInterfaces {
+			if strings.EqualFold(iface.Name, lagName) {
+				(*deviceInterfaces)[index].Members = append((*deviceInterfaces)[index].Members, members...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			var aggr model.FortigateInterface
+			aggr.InterfaceType = "aggregate"
+			aggr.Name = lagName
+			aggr.Members = members
+			*deviceInterfaces = append(*deviceInterfaces, aggr)
+		}
+	}
+}
+
+// maskToCIDR converts an address with a dotted netmask or mask length to prefix notation.
+func maskToCIDR(address string, netmask string) string {
+	ip := net.ParseIP(address)
+	if prefixLength, err := strconv.Atoi(netmask); err == nil && ip != nil {
+		return fmt.Sprintf("%s/%d", address, prefixLength)
+	}
+	mask := net.ParseIP(netmask).To4()
+	if ip == nil || mask == nil {
+		return ""
+	}
+	prefixLength, bits := net.IPMask(mask).Size()
+	if bits == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", address, prefixLength)
+}
+
+// findFhrpGroup returns the group with the protocol and group id, the group is
+// added when it does not exist yet.
+func findFhrpGroup(groups *[]model.FhrpGroup, protocol string, groupId string) *model.FhrpGroup {
+	for index, group := range *groups {
+		if group.GroupId == groupId && group.Protocol == protocol {
+			return &(*groups)[index]
+		}
+	}
+	*groups = append(*groups, model.FhrpGroup{Protocol: protocol, GroupId: groupId})
+	return &(*groups)[len(*groups)-1]
+}
+
+// applyFhrpCommand applies a "standby" or "vrrp" interface line without the keyword,
+// like "1 ip 10.0.0.254" or "1 priority 110". HSRP lines without a group use group 0.
+func applyFhrpCommand(groups *[]model.FhrpGroup, protocol string, command string) {
+	fields := strings.Fields(command)
+	groupId := "0"
+	if len(fields) > 0 {
+		if _, err := strconv.Atoi(fields[0]); err == nil {
+			groupId = fields[0]
+			fields = fields[1:]
+		}
+	}
+	if len(fields) < 2 {
+		return
+	}
+
+	switch fields[0] {
+	case "ip", "ipv4", "ipv6":
+		if net.ParseIP(strings.Split(fields[1], "/")[0]) == nil {
+			return
+		}
+		group := findFhrpGroup(groups, protocol, groupId)
+		group.VirtualIPs = append(group.VirtualIPs, fields[1])
+	case "priority", "priority-level":
+		findFhrpGroup(groups, protocol, groupId).Priority = fields[1]
+	}
+}
@@ -0,0 +1,53 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExpandVlanList(t *testing.T) {
+	tests := []struct {
+		list string
+		want []string
+	}{
+		{"", nil},
+		{"10", []string{"10"}},
+		{"10,20-22 30", []string{"10", "20", "21", "22", "30"}},
+		{"10,,20", []string{"10", "20"}},
+		{"5-3", nil},
+		{"a,10,b-20,30-c", []string{"10"}},
+	}
+	for _, tt := range tests {
+		if got := expandVlanList(tt.list); !slices.Equal(got, tt.want) {
+			t.Errorf("expandVlanList(%q) = %v, want %v", tt.list, got, tt.want)
+		}
+	}
+}
+
+func TestApplyVlanListCommand(t *testing.T) {
+	tests := []struct {
+		current  []string
+		argument string
+		want     []string
+		allowAll bool
+	}{
+		{nil, "10,20", []string{"10", "20"}, false},
+		{[]string{"10"}, "20-21", []string{"20", "21"}, false},
+		{[]string{"10"}, "add 20,10", []string{"10", "20"}, false},
+		{[]string{"10", "20", "30"}, "remove 20", []string{"10", "30"}, false},
+		{[]string{"10"}, "all", nil, true},
+		{[]string{"10"}, "none", []string{}, false},
+		{nil, "1-4094", nil, true},
+	}
+	for _, tt := range tests {
+		got, allowAll := applyVlanListCommand(slices.Clone(tt.current), tt.argument)
+		if !slices.Equal(got, tt.want) || allowAll != tt.allowAll {
+			t.Errorf("applyVlanListCommand(%v, %q) = %v, %v, want %v, %v", tt.current, tt.argument, got, allowAll, tt.want, tt.allowAll)
+		}
+	}
+
+	except, allowAll := applyVlanListCommand(nil, "except 1-4000")
+	if allowAll || len(except) != 94 || except[0] != "4001" || except[93] != "4094" {
+		t.Errorf("applyVlanListCommand(except 1-4000) = %d vlans, allowAll %v, want 4001-4094", len(except), allowAll)
+	}
+}
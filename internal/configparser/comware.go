@@ -0,0 +1,17 @@
+package configparser
+
+import "github.com/mattieserver/netbox-oxidized-sync/internal/model"
+
+var hpeComware = vrpDialect{
+	lagMember:         "port link-aggregation group ",
+	lagName:           "Bridge-Aggregation",
+	aggregatePrefixes: []string{"Bridge-Aggregation", "Route-Aggregation"},
+	trunkAllowed:      "port trunk permit vlan ",
+	accessVlan:        "port access vlan ",
+	vlanInterface:     "Vlan-interface",
+	subinterfaceVid:   "vlan-type dot1q vid ",
+}
+
+func ParseComwareConfig(config *string) (*[]model.FortigateInterface, error) {
+	return parseVRPStyleConfig(config, hpeComware)
+}
@@ -0,0 +1,237 @@
+package configparser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	cumulusIface           = "iface "
+	cumulusDefaultPvid     = "1"
+	cumulusBondSlaves      = "bond-slaves"
+	cumulusBridgePorts     = "bridge-ports"
+	cumulusBridgeVids      = "bridge-vids"
+	cumulusBridgePvid      = "bridge-pvid"
+	cumulusBridgeAccess    = "bridge-access"
+	cumulusBridgeVlanVni   = "bridge-vlan-vni-map"
+	cumulusBridgeVlanAware = "bridge-vlan-aware"
+	cumulusAddress         = "address"
+	cumulusAlias           = "alias"
+	cumulusLinkDown        = "link-down"
+	cumulusVrf             = "vrf"
+	cumulusVrfTable        = "vrf-table"
+	cumulusVlanId          = "vlan-id"
+	cumulusVlanRawDevice   = "vlan-raw-device"
+	cumulusVxlanId         = "vxlan-id"
+	cumulusLoopbackName    = "lo"
+	cumulusGlobKeyword     = "glob"
+)
+
+var cumulusPortRange = regexp.MustCompile(`^(.*?)(\d+)-(\d+)$`)
+
+// cumulusStanza holds the options of an ifupdown2 "iface" stanza, an option can occur multiple times.
+type cumulusStanza struct {
+	name    string
+	options map[string][]string
+}
+
+func (stanza *cumulusStanza) option(key string) string {
+	if values := stanza.options[key]; len(values) > 0 {
+		return values[len(values)-1]
+	}
+	return ""
+}
+
+// ParseCumulusConfig parses the ifupdown2 /etc/network/interfaces file of Cumulus Linux.
+func ParseCumulusConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+
+	stanzas := parseCumulusStanzas(config)
+
+	lagMembers := map[string][]string{}
+	bridgeMembers := map[string]*cumulusStanza{}
+	var bridges []model.FortigateVirtualSwitch
+
+	for _, stanza := range stanzas {
+		if ports := stanza.option(cumulusBridgePorts); ports != "" {
+			members := expandCumulusPorts(ports)
+			for _, member := range members {
+				bridgeMembers[member] = stanza
+			}
+			bridges = append(bridges, model.FortigateVirtualSwitch{Name: stanza.name, Members: members})
+		}
+	}
+
+	for _, stanza := range stanzas {
+		var iface model.FortigateInterface
+		iface.Name = stanza.name
+		iface.Description = stanza.option(cumulusAlias)
+		iface.Vrf = stanza.option(cumulusVrf)
+		if stanza.option(cumulusLinkDown) == "yes" {
+			iface.Status = "down"
+		}
+		for _, address := range stanza.options[cumulusAddress] {
+			if strings.Contains(address, "/") {
+				iface.IPAddresses = append(iface.IPAddresses, address)
+			}
+		}
+
+		// the bridge keeps its addresses and vrf, convertVirtualSwitch sets the type and members
+		if stanza.option(cumulusBridgePorts) != "" {
+			deviceInterfaces = append(deviceInterfaces, iface)
+			continue
+		}
+
+		baseName, subinterfaceVid, isSubinterface := strings.Cut(stanza.name, ".")
+
+		switch {
+		case stanza.option(cumulusBondSlaves) != "":
+			iface.InterfaceType = "aggregate"
+			lagMembers[stanza.name] = expandCumulusPorts(stanza.option(cumulusBondSlaves))
+		case isSubinterface:
+			iface.InterfaceType = "vlan"
+			iface.Parent = baseName
+			iface.VlanId = subinterfaceVid
+		case stanza.option(cumulusVlanId) != "" || stanza.option(cumulusVlanRawDevice) != "":
+			iface.InterfaceType = "vlan"
+			iface.VlanId = stanza.option(cumulusVlanId)
+		case stanza.option(cumulusVxlanId) != "" || stanza.option(cumulusBridgeVlanVni) != "":
+			iface.InterfaceType = "virtual"
+			iface.VniMappings = cumulusVniMappings(stanza)
+		case stanza.name == cumulusLoopbackName, stanza.option(cumulusVrfTable) != "":
+			iface.InterfaceType = "virtual"
+		default:
+			iface.InterfaceType = "physical"
+		}
+
+		if bridge, ok := bridgeMembers[stanza.name]; ok && bridge.option(cumulusBridgeVlanAware) == "yes" && (iface.InterfaceType == "physical" || iface.InterfaceType == "aggregate") {
+			applyCumulusBridging(&iface, stanza, bridge)
+		}
+
+		deviceInterfaces = append(deviceInterfaces, iface)
+	}
+
+	addLagMembers(&deviceInterfaces, lagMembers)
+	convertVirtualSwitch(&bridges, &deviceInterfaces)
+
+	return &deviceInterfaces, nil
+}
+
+func parseCumulusStanzas(config *string) []*cumulusStanza {
+	var result []*cumulusStanza
+	byName := map[string]*cumulusStanza{}
+
+	for _, section := range parseIndentedSections(config, cumulusIface) {
+		fields := strings.Fields(section.header)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// an interface can have multiple stanzas, like "iface eth0 inet dhcp" and "iface eth0 inet6 auto"
+		stanza, ok := byName[fields[0]]
+		if !ok {
+			stanza = &cumulusStanza{name: fields[0], options: map[string][]string{}}
+			byName[fields[0]] = stanza
+			result = append(result, stanza)
+		}
+
+		for _, line := range section.lines {
+			if strings.HasPrefix(line, "#") {
+				continue
+			}
+			key, value, _ := strings.Cut(line, " ")
+			stanza.options[key] = append(stanza.options[key], strings.Trim(strings.TrimSpace(value), "\""))
+		}
+	}
+
+	return result
+}
+
+// applyCumulusBridging sets the vlans of a port in a vlan-aware bridge, ports of a
+// traditional bridge keep their own vlan. The port inherits the vids and pvid of the bridge when it does not set them itself.
+func applyCumulusBridging(iface *model.FortigateInterface, port *cumulusStanza, bridge *cumulusStanza) {
+	if access := port.option(cumulusBridgeAccess); access != "" {
+		iface.VlanMode = vlanModeAccess
+		iface.VlanId = access
+		return
+	}
+
+	vids := port.option(cumulusBridgeVids)
+	if vids == "" {
+		vids = bridge.option(cumulusBridgeVids)
+	}
+	pvid := port.option(cumulusBridgePvid)
+	if pvid == "" {
+		pvid = bridge.option(cumulusBridgePvid)
+	}
+	if pvid == "" {
+		pvid = cumulusDefaultPvid
+	}
+
+	iface.VlanMode = vlanModeTagged
+	iface.VlanId = pvid
+	for _, vid := range expandVlanList(vids) {
+		if vid != pvid {
+			iface.TaggedVlans = append(iface.TaggedVlans, vid)
+		}
+	}
+}
+
+// cumulusVniMappings supports both a vxlan device per vni and the single vxlan device with a vlan-vni map.
+func cumulusVniMappings(stanza *cumulusStanza) map[string]string {
+	mappings := map[string]string{}
+
+	if vni := stanza.option(cumulusVxlanId); vni != "" {
+		if vlan := stanza.option(cumulusBridgeAccess); vlan != "" {
+			mappings[vlan] = vni
+		}
+	}
+
+	// bridge-vlan-vni-map 10=10010 20-21=10020-10021
+	for _, mapping := range strings.Fields(stanza.option(cumulusBridgeVlanVni)) {
+		vlans, vnis, ok := strings.Cut(mapping, "=")
+		if !ok {
+			continue
+		}
+		vlanList := expandVlanList(vlans)
+		vniList := expandVlanList(vnis)
+		for index, vlan := range vlanList {
+			if index < len(vniList) {
+				mappings[vlan] = vniList[index]
+			}
+		}
+	}
+
+	return mappings
+}
+
+// expandCumulusPorts expands "swp1 glob swp3-5" to the separate port names.
+func expandCumulusPorts(list string) []string {
+	var result []string
+	glob := false
+
+	for _, field := range strings.Fields(list) {
+		if field == cumulusGlobKeyword {
+			glob = true
+			continue
+		}
+		match := cumulusPortRange.FindStringSubmatch(field)
+		if !glob || match == nil {
+			result = append(result, field)
+			glob = false
+			continue
+		}
+		glob = false
+
+		start, _ := strconv.Atoi(match[2])
+		end, _ := strconv.Atoi(match[3])
+		for port := start; port <= end; port++ {
+			result = append(result, match[1]+strconv.Itoa(port))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,78 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+)
+
+const cumulusBridgeFixture = `auto swp1
+iface swp1
+    alias uplink
+
+auto swp2
+iface swp2
+    bridge-access 20
+
+auto swp3
+iface swp3
+    bridge-vids 10 30
+
+auto swp4.100
+iface swp4.100
+
+auto swp5
+iface swp5
+
+auto bridge
+iface bridge
+    bridge-vlan-aware yes
+    bridge-ports swp1 swp2 swp3
+    bridge-vids 10 20
+    bridge-pvid 10
+
+auto br100
+iface br100
+    bridge-ports swp4.100 swp5
+    address 192.0.2.1/24
+    vrf customer
+`
+
+func TestParseCumulusConfigBridges(t *testing.T) {
+	config := cumulusBridgeFixture
+	interfaces, err := ParseCumulusConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		vlanMode    string
+		vlanId      string
+		taggedVlans []string
+		parent      string
+	}{
+		{"swp1", vlanModeTagged, "10", []string{"20"}, "bridge"},
+		{"swp2", vlanModeAccess, "20", nil, "bridge"},
+		{"swp3", vlanModeTagged, "10", []string{"30"}, "bridge"},
+		{"swp4.100", "", "100", nil, "swp4"},
+		{"swp5", "", "", nil, "br100"},
+	}
+	for _, tt := range tests {
+		iface := findInterface(t, interfaces, tt.name)
+		if iface.VlanMode != tt.vlanMode || iface.VlanId != tt.vlanId || !slices.Equal(iface.TaggedVlans, tt.taggedVlans) || iface.Parent != tt.parent {
+			t.Errorf("%s: got mode %q vlan %q tagged %v parent %q, want mode %q vlan %q tagged %v parent %q",
+				tt.name, iface.VlanMode, iface.VlanId, iface.TaggedVlans, iface.Parent, tt.vlanMode, tt.vlanId, tt.taggedVlans, tt.parent)
+		}
+	}
+
+	bridge := findInterface(t, interfaces, "br100")
+	if bridge.InterfaceType != "virtual-switch" {
+		t.Errorf("br100: got type %q, want virtual-switch", bridge.InterfaceType)
+	}
+	if !slices.Equal(bridge.Members, []string{"swp4.100", "swp5"}) {
+		t.Errorf("br100: got members %v", bridge.Members)
+	}
+	if bridge.Vrf != "customer" || !slices.Equal(bridge.IPAddresses, []string{"192.0.2.1/24"}) {
+		t.Errorf("br100: got vrf %q addresses %v, want customer [192.0.2.1/24]", bridge.Vrf, bridge.IPAddresses)
+	}
+}
@@ -0,0 +1,141 @@
+package configparser
+
+import (
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	eosEncapsulation   = "encapsulation dot1q vlan "
+	eosMlag            = "mlag "
+	eosVrf             = "vrf "
+	eosVrfForwarding   = "vrf forwarding "
+	eosVxlanVlan       = "vxlan vlan "
+	eosPortChannelName = "Port-Channel"
+)
+
+func ParseEOSConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+	channelGroups := map[string][]string{}
+
+	for _, section := range parseIndentedSections(config, iosInterfacePrefix) {
+		iface, channelGroup := parseEOSInterface(section)
+		if iface.Name == "" {
+			continue
+		}
+		if channelGroup != "" {
+			lagName := eosPortChannelName + channelGroup
+			channelGroups[lagName] = append(channelGroups[lagName], iface.Name)
+		}
+		deviceInterfaces = append(deviceInterfaces, iface)
+	}
+
+	addLagMembers(&deviceInterfaces, channelGroups)
+
+	return &deviceInterfaces, nil
+}
+
+func parseEOSInterface(section configSection) (model.FortigateInterface, string) {
+	var iface model.FortigateInterface
+	var channelGroup, accessVlan, nativeVlan, mlag, description string
+	var allowedVlans []string
+	allowAll := true
+
+	fields := strings.Fields(section.header)
+	if len(fields) == 0 {
+		return iface, ""
+	}
+	iface.Name = fields[0]
+
+	for _, line := range section.lines {
+		switch {
+		case strings.HasPrefix(line, iosDescription):
+			description = strings.TrimPrefix(line, iosDescription)
+		case line == iosShutdown:
+			iface.Status = "down"
+		case strings.HasPrefix(line, iosChannelGroup):
+			channelGroup = strings.Fields(strings.TrimPrefix(line, iosChannelGroup))[0]
+		case strings.HasPrefix(line, eosEncapsulation):
+			iface.VlanId = strings.TrimPrefix(line, eosEncapsulation)
+		case strings.HasPrefix(line, eosMlag):
+			mlag = strings.TrimPrefix(line, eosMlag)
+		case strings.HasPrefix(line, eosVrfForwarding):
+			iface.Vrf = strings.TrimPrefix(line, eosVrfForwarding)
+		case strings.HasPrefix(line, eosVrf):
+			iface.Vrf = strings.TrimPrefix(line, eosVrf)
+		case strings.HasPrefix(line, iosIPAddress), strings.HasPrefix(line, iosIPv6Address):
+			// ip address 10.0.0.1/24 [secondary], "ip address virtual" is an anycast gateway
+			addressFields := strings.Fields(line)
+			if len(addressFields) > 2 && strings.Contains(addressFields[2], "/") {
+				iface.IPAddresses = append(iface.IPAddresses, addressFields[2])
+			}
+		case strings.HasPrefix(line, eosVxlanVlan):
+			vlanList, vniList, found := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(line, eosVxlanVlan), "add "), " vni ")
+			vlanIds, vnis := expandVlanList(vlanList), expandVlanList(vniList)
+			if found && len(vlanIds) == len(vnis) {
+				if iface.VniMappings == nil {
+					iface.VniMappings = map[string]string{}
+				}
+				for index, vlanId := range vlanIds {
+					iface.VniMappings[vlanId] = vnis[index]
+				}
+			}
+		case strings.HasPrefix(line, iosSwitchportMode):
+			switch strings.TrimPrefix(line, iosSwitchportMode) {
+			case "access":
+				iface.VlanMode = vlanModeAccess
+			case "trunk":
+				iface.VlanMode = vlanModeTagged
+			}
+		case strings.HasPrefix(line, iosAccessVlan):
+			accessVlan = strings.TrimPrefix(line, iosAccessVlan)
+		case strings.HasPrefix(line, iosNativeVlan):
+			nativeVlan = strings.TrimPrefix(line, iosNativeVlan)
+		case strings.HasPrefix(line, iosTrunkAllowedVlan):
+			allowedVlans, allowAll = applyVlanListCommand(allowedVlans, strings.TrimPrefix(line, iosTrunkAllowedVlan))
+		case strings.HasPrefix(line, iosVrrp):
+			applyFhrpCommand(&iface.FhrpGroups, fhrpVrrp2, strings.TrimPrefix(line, iosVrrp))
+		}
+	}
+
+	// EOS ports are access ports unless configured otherwise
+	if iface.VlanMode == "" && accessVlan != "" {
+		iface.VlanMode = vlanModeAccess
+	}
+
+	switch iface.VlanMode {
+	case vlanModeAccess:
+		iface.VlanId = accessVlan
+	case vlanModeTagged:
+		iface.VlanId = nativeVlan
+		iface.VlanMode = trunkVlanMode(allowAll)
+		iface.TaggedVlans = allowedVlans
+	}
+
+	iface.Description = description
+	if mlag != "" {
+		createDescriptionBuilder(mlag, "mlag", &iface.Description)
+	}
+
+	baseName, _, isSubinterface := strings.Cut(iface.Name, ".")
+
+	switch {
+	case isSubinterface:
+		iface.InterfaceType = "vlan"
+		iface.Parent = baseName
+		iface.VlanMode = ""
+		iface.TaggedVlans = nil
+	case strings.HasPrefix(iface.Name, iosVlanInterfacePrefix):
+		iface.InterfaceType = "vlan"
+		iface.VlanId = strings.TrimPrefix(iface.Name, iosVlanInterfacePrefix)
+	case strings.HasPrefix(iface.Name, eosPortChannelName):
+		iface.InterfaceType = "aggregate"
+	case strings.HasPrefix(iface.Name, "Loopback"), strings.HasPrefix(iface.Name, "Vxlan"), strings.HasPrefix(iface.Name, "Tunnel"):
+		iface.InterfaceType = "virtual"
+	default:
+		iface.InterfaceType = "physical"
+	}
+
+	return iface, channelGroup
+}
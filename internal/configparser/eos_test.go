@@ -0,0 +1,111 @@
+package configparser
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+const eosFixture = `! device: leaf1 (DCS-7050SX3-48YC8, EOS-4.28.3M)
+!
+interface Port-Channel10
+   description peer
+   switchport mode trunk
+   switchport trunk native vlan 99
+   switchport trunk allowed vlan 10,20-21
+   mlag 10
+!
+interface Ethernet1
+   channel-group 10 mode active
+!
+interface Ethernet2
+   channel-group 10 mode active
+!
+interface Ethernet3
+   switchport access vlan 30
+!
+interface Ethernet4
+   no switchport
+   ip address 192.0.2.0/31
+!
+interface Ethernet4.100
+   encapsulation dot1q vlan 100
+   vrf CUST
+   ip address 198.51.100.1/24
+!
+interface Ethernet5
+   shutdown
+!
+interface Loopback0
+   ip address 10.255.0.1/32
+!
+interface Vlan10
+   vrf forwarding CUST
+   ip address 10.0.10.2/24
+   ip address virtual 10.0.10.1/24
+   ipv6 address 2001:db8:10::2/64
+!
+interface Vxlan1
+   vxlan vlan 10 vni 10010
+   vxlan vlan add 20-21 vni 10020-10021
+!
+router bgp 65001
+   neighbor 192.0.2.1 remote-as 65000
+`
+
+func TestParseEOSConfig(t *testing.T) {
+	config := eosFixture
+	interfaces, err := ParseEOSConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		interfaceType string
+		status        string
+		parent        string
+		vlanMode      string
+		vlanId        string
+		taggedVlans   []string
+		members       []string
+		vrf           string
+		ipAddresses   []string
+	}{
+		{"Port-Channel10", "aggregate", "", "", vlanModeTagged, "99", []string{"10", "20", "21"}, []string{"Ethernet1", "Ethernet2"}, "", nil},
+		{"Ethernet1", "physical", "", "", "", "", nil, nil, "", nil},
+		{"Ethernet3", "physical", "", "", vlanModeAccess, "30", nil, nil, "", nil},
+		{"Ethernet4", "physical", "", "", "", "", nil, nil, "", []string{"192.0.2.0/31"}},
+		{"Ethernet4.100", "vlan", "", "Ethernet4", "", "100", nil, nil, "CUST", []string{"198.51.100.1/24"}},
+		{"Ethernet5", "physical", "down", "", "", "", nil, nil, "", nil},
+		{"Loopback0", "virtual", "", "", "", "", nil, nil, "", []string{"10.255.0.1/32"}},
+		{"Vlan10", "vlan", "", "", "", "10", nil, nil, "CUST", []string{"10.0.10.2/24", "2001:db8:10::2/64"}},
+		{"Vxlan1", "virtual", "", "", "", "", nil, nil, "", nil},
+	}
+	for _, tt := range tests {
+		iface := findInterface(t, interfaces, tt.name)
+		if iface.InterfaceType != tt.interfaceType || iface.Status != tt.status || iface.Parent != tt.parent {
+			t.Errorf("%s: got type %q status %q parent %q, want %q %q %q", tt.name, iface.InterfaceType, iface.Status, iface.Parent, tt.interfaceType, tt.status, tt.parent)
+		}
+		if iface.VlanMode != tt.vlanMode || iface.VlanId != tt.vlanId || !slices.Equal(iface.TaggedVlans, tt.taggedVlans) {
+			t.Errorf("%s: got mode %q vlan %q tagged %v, want %q %q %v", tt.name, iface.VlanMode, iface.VlanId, iface.TaggedVlans, tt.vlanMode, tt.vlanId, tt.taggedVlans)
+		}
+		if !slices.Equal(iface.Members, tt.members) {
+			t.Errorf("%s: got members %v, want %v", tt.name, iface.Members, tt.members)
+		}
+		if iface.Vrf != tt.vrf || !slices.Equal(iface.IPAddresses, tt.ipAddresses) {
+			t.Errorf("%s: got vrf %q addresses %v, want %q %v", tt.name, iface.Vrf, iface.IPAddresses, tt.vrf, tt.ipAddresses)
+		}
+	}
+
+	if got := findInterface(t, interfaces, "Port-Channel10").Description; got != "peer; mlag: 10" {
+		t.Errorf("Port-Channel10: got description %q", got)
+	}
+	want := map[string]string{"10": "10010", "20": "10020", "21": "10021"}
+	if got := findInterface(t, interfaces, "Vxlan1").VniMappings; !maps.Equal(got, want) {
+		t.Errorf("Vxlan1: got vni mappings %v, want %v", got, want)
+	}
+	if len(*interfaces) != 10 {
+		t.Errorf("got %d interfaces, want 10", len(*interfaces))
+	}
+}
@@ -1,220 +1,306 @@
 package configparser
 
 import (
-	"bufio"
 	"fmt"
-	"log/slog"
+	"slices"
 	"strings"
 
 	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
 )
 
-const (
-	interfaceNamePrefix            = "    edit "
-	interfaceTypePrefix            = "        set type "
-	interfaceVlanIdPrefix          = "        set vlanid "
-	interfaceParentInterfacePrefix = "        set interface "
-	interfaceAliasPrefix           = "        set alias "
-	interfaceVdomPrefix            = "        set vdom "
-	interfaceIp                    = "        set ip "
-	interfaceSpeed                 = "        set speed "
-	intefaceMember                 = "        set member "
-	interfaceStatus                = "        set status "
-	interfaceDescription           = "        set description "
-	virtualSwitchPortPrefix        = "            edit "
-)
-
 func ParseFortiOSConfig(config *string) (*[]model.FortigateInterface, error) {
 	const (
-		start              = "config system interface"
-		end                = "end"
-		startVirtualSwitch = "config system virtual-switch"
+		sectionInterface     = "system interface"
+		sectionVirtualSwitch = "system virtual-switch"
+		sectionZone          = "system zone"
+		sectionPhase1        = "vpn ipsec phase1-interface"
+		sectionGreTunnel     = "system gre-tunnel"
+		sectionSwitchIface   = "system switch-interface"
+		sectionSdwan         = "system sdwan"
+		sectionWanLink       = "system virtual-wan-link"
 	)
 
-	var (
-		configVirtualSwitchTracking bool
-		configInterfacesTracking    bool
-		configInterfaces            []string
-		configVirtualSwitch         []string
-	)
+	tree := parseFortiOSTree(config)
+
+	deviceInterfaces := parseInterfaces(tree.sections(sectionInterface))
+	deviceVirtualSwitches := parseVirtualSwitch(tree.sections(sectionVirtualSwitch))
+	*deviceVirtualSwitches = append(*deviceVirtualSwitches, *parseSwitchInterfaces(tree.sections(sectionSwitchIface))...)
+	convertVirtualSwitch(deviceVirtualSwitches, deviceInterfaces)
+	applyVlanSwitchTrunks(deviceVirtualSwitches, deviceInterfaces)
 
-	scanner := bufio.NewScanner(strings.NewReader(*config))
-	for scanner.Scan() {
-		line := scanner.Text()
-		switch {
-		case configInterfacesTracking && line == end:
-			configInterfacesTracking = false
-		case configVirtualSwitchTracking && line == end:
-			configVirtualSwitchTracking = false
-		case configVirtualSwitchTracking:
-			configVirtualSwitch = append(configVirtualSwitch, line)
-		case configInterfacesTracking:
-			configInterfaces = append(configInterfaces, line)
-		case line == start:
-			configInterfacesTracking = true
-		case line == startVirtualSwitch:
-			configVirtualSwitchTracking = true
+	// without "config global" every interface is in the root vdom, only multi-vdom
+	// configs are mapped to virtual device contexts
+	if tree.config(fortiosGlobal) == nil {
+		for index := range *deviceInterfaces {
+			(*deviceInterfaces)[index].Vdom = ""
 		}
 	}
 
-	deviceInterfaces := parseInterfaces(configInterfaces)
-	deviceVirtualSwitches := parseVirtualSwitch(configVirtualSwitch)
-	convertVirtualSwitch(deviceVirtualSwitches, deviceInterfaces)
+	zones := parseZones(tree.sections(sectionZone))
+	for index, dinterface := range *deviceInterfaces {
+		(*deviceInterfaces)[index].Zone = zones[fortiosObjectName(dinterface)]
+	}
+
+	parseTunnels(tree.sections(sectionPhase1), "ipsec-tunnel", deviceInterfaces)
+	parseTunnels(tree.sections(sectionGreTunnel), "gre", deviceInterfaces)
+
+	// FortiOS 6.4 renamed virtual-wan-link to sdwan
+	parseSdwan(tree.sections(sectionSdwan), deviceInterfaces)
+	parseSdwan(tree.sections(sectionWanLink), deviceInterfaces)
 
 	return deviceInterfaces, nil
 }
 
-func parseVirtualSwitch(virtualSwitches []string) *[]model.FortigateVirtualSwitch{
-
-	var deviceVirtualSwitches []model.FortigateVirtualSwitch
+// fortiosObjectName returns the name zones, tunnels and sd-wan members use to
+// reference the interface, a vlan can be synced with its alias instead.
+func fortiosObjectName(iface model.FortigateInterface) string {
+	if iface.ObjectName != "" {
+		return iface.ObjectName
+	}
+	return iface.Name
+}
 
-	var (
-		configVirtualSwitch         []string
-		configVirtualSwitchTracking bool
-	)
+func parseZones(sections []fortiosSection) map[string]string {
+	var interfaceZones = map[string]string{}
 
-	for _, element := range virtualSwitches {
-		if strings.HasPrefix(element, interfaceNamePrefix) {
-			configVirtualSwitchTracking = true
-			configVirtualSwitch = []string{element}
-			continue
+	for _, section := range sections {
+		for _, zone := range section.config.entries {
+			for _, member := range zone.values("interface") {
+				interfaceZones[member] = zone.name
+			}
 		}
+	}
+
+	return interfaceZones
+}
+
+// parseTunnels sets the tunnel on the interface FortiOS creates for every ipsec
+// phase1-interface and gre-tunnel. Without a local-gw the first address of the
+// underlying interface is the local gateway.
+func parseTunnels(sections []fortiosSection, encapsulation string, deviceInterfaces *[]model.FortigateInterface) {
+	for _, section := range sections {
+		for _, entry := range section.config.entries {
+			tunnel := model.Tunnel{Encapsulation: encapsulation}
+			tunnel.RemoteGateway = entry.value("remote-gw")
+			if tunnel.RemoteGateway == "" || tunnel.RemoteGateway == "0.0.0.0" {
+				tunnel.RemoteGateway = entry.value("remote-gw6")
+			}
+			// dialup tunnels have no fixed remote gateway
+			if tunnel.RemoteGateway == "::" {
+				tunnel.RemoteGateway = ""
+			}
+			tunnel.LocalGateway = entry.value("local-gw")
+			if tunnel.LocalGateway == "0.0.0.0" {
+				tunnel.LocalGateway = ""
+			}
+
+			underlay := entry.value("interface")
+			underlayIndex := slices.IndexFunc(*deviceInterfaces, func(iface model.FortigateInterface) bool { return fortiosObjectName(iface) == underlay })
+			if underlayIndex != -1 {
+				underlay = (*deviceInterfaces)[underlayIndex].Name
+			}
 
-		if strings.HasPrefix(element, "    next") {
-			if configVirtualSwitchTracking {
-				configVirtualSwitchTracking = false
-				parseSingleVirtualSwitch(configVirtualSwitch, &deviceVirtualSwitches)
+			index := slices.IndexFunc(*deviceInterfaces, func(iface model.FortigateInterface) bool { return fortiosObjectName(iface) == entry.name })
+			if index == -1 {
+				var virt model.FortigateInterface
+				virt.InterfaceType = "virtual"
+				virt.Name = entry.name
+				virt.Parent = underlay
+				virt.Vdom = section.vdom
+				*deviceInterfaces = append(*deviceInterfaces, virt)
+				index = len(*deviceInterfaces) - 1
 			}
+
+			if tunnel.LocalGateway == "" && underlayIndex != -1 && len((*deviceInterfaces)[underlayIndex].IPAddresses) > 0 {
+				tunnel.LocalGateway, _, _ = strings.Cut((*deviceInterfaces)[underlayIndex].IPAddresses[0], "/")
+			}
+			(*deviceInterfaces)[index].Tunnel = &tunnel
+		}
+	}
+}
+
+// parseSdwan sets the SD-WAN zone, gateway and priority of the member interfaces.
+// Members without a zone are in the default "virtual-wan-link" zone.
+func parseSdwan(sections []fortiosSection, deviceInterfaces *[]model.FortigateInterface) {
+	for _, section := range sections {
+		members := section.config.config("members")
+		if members == nil {
 			continue
 		}
+		for _, member := range members.entries {
+			index := slices.IndexFunc(*deviceInterfaces, func(iface model.FortigateInterface) bool { return fortiosObjectName(iface) == member.value("interface") })
+			if index == -1 {
+				continue
+			}
 
-		if configVirtualSwitchTracking {
-			configVirtualSwitch = append(configVirtualSwitch, element)
+			zone := member.value("zone")
+			if zone == "" {
+				zone = "virtual-wan-link"
+			}
+			(*deviceInterfaces)[index].SdwanZone = zone
+
+			gateway := member.value("gateway")
+			if gateway == "" || gateway == "0.0.0.0" {
+				gateway = member.value("gateway6")
+			}
+			if gateway != "" && gateway != "::" {
+				(*deviceInterfaces)[index].SdwanGateway = gateway
+			}
+			(*deviceInterfaces)[index].SdwanPriority = member.value("priority")
 		}
 	}
-	return &deviceVirtualSwitches
 }
 
-func parseSingleVirtualSwitch(virtualSwitchData []string, results *[]model.FortigateVirtualSwitch)  {
-	var name string
-	var portNames []string
+func parseVirtualSwitch(sections []fortiosSection) *[]model.FortigateVirtualSwitch {
 
-	portPrefixes := map[string]*[]string{
-		virtualSwitchPortPrefix: &portNames,
-	}
+	var deviceVirtualSwitches []model.FortigateVirtualSwitch
 
-	prefixes := map[string]*string{
-		interfaceNamePrefix: &name,
+	for _, section := range sections {
+		for _, entry := range section.config.entries {
+			parseSingleVirtualSwitch(entry, &deviceVirtualSwitches)
+		}
 	}
+	return &deviceVirtualSwitches
+}
 
-	for _, element := range virtualSwitchData {
-		for prefix, value := range prefixes {
-			if strings.HasPrefix(element, prefix) {
-				*value = getElementValue(element, prefix)
-			}
-		}
+func parseSingleVirtualSwitch(virtualSwitchData *fortiosNode, results *[]model.FortigateVirtualSwitch)  {
+	var portNames []string
 
-		for portPrefix, portValue := range portPrefixes {
-			if strings.HasPrefix(element, portPrefix) {
-				*portValue = append(*portValue, getElementValue(element, portPrefix))
-			}
+	if ports := virtualSwitchData.config("port"); ports != nil {
+		for _, port := range ports.entries {
+			portNames = append(portNames, port.name)
 		}
 	}
 
-	if name == "''" {
+	if virtualSwitchData.name == "''" || virtualSwitchData.name == "" {
 		return
 	}
 
 	var vSwitch model.FortigateVirtualSwitch
-	vSwitch.Name = name
+	vSwitch.Name = virtualSwitchData.name
 	vSwitch.Members = portNames
+	// vlan switch mode, the ports of the switch are untagged in the vlan
+	if vlanId := virtualSwitchData.value("vlan"); vlanId != "0" {
+		vSwitch.VlanId = vlanId
+	}
 	*results = append(*results, vSwitch)
 }
 
-func parseInterfaces(interfaces []string) *[]model.FortigateInterface {
+// parseSwitchInterfaces returns the software switches, their members are set on the
+// switch-interface entry instead of in a port block.
+func parseSwitchInterfaces(sections []fortiosSection) *[]model.FortigateVirtualSwitch {
+	var switchInterfaces []model.FortigateVirtualSwitch
 
-	var deviceInterfaces []model.FortigateInterface
-
-	var (
-		configInterface         []string
-		configInterfaceTracking bool
-	)
-
-	for _, element := range interfaces {
+	for _, section := range sections {
+		for _, entry := range section.config.entries {
+			if entry.name == "''" || entry.name == "" {
+				continue
+			}
+			var vSwitch model.FortigateVirtualSwitch
+			vSwitch.Name = entry.name
+			vSwitch.Members = entry.values("member")
+			switchInterfaces = append(switchInterfaces, vSwitch)
+		}
+	}
+	return &switchInterfaces
+}
 
-		if strings.HasPrefix(element, interfaceNamePrefix) {
-			configInterfaceTracking = true
-			configInterface = []string{element}
-			continue
+// applyVlanSwitchTrunks sets the vlans of all vlan switches as tagged vlans on the
+// trunk ports, a trunk port carries every vlan switch.
+func applyVlanSwitchTrunks(virtutalSwitches *[]model.FortigateVirtualSwitch, deviceInterfaces *[]model.FortigateInterface) {
+	var vlanIds []string
+	for _, vswitch := range *virtutalSwitches {
+		if vswitch.VlanId != "" && !slices.Contains(vlanIds, vswitch.VlanId) {
+			vlanIds = append(vlanIds, vswitch.VlanId)
 		}
+	}
 
-		if strings.HasPrefix(element, "    next") {
-			if configInterfaceTracking {
-				configInterfaceTracking = false
-				parseSingleInterface(configInterface, &deviceInterfaces)
-			}
+	for index, dinterface := range *deviceInterfaces {
+		if dinterface.VlanMode != vlanModeTagged || dinterface.TaggedVlans != nil {
 			continue
 		}
+		if len(vlanIds) == 0 {
+			(*deviceInterfaces)[index].VlanMode = ""
+		} else {
+			(*deviceInterfaces)[index].TaggedVlans = vlanIds
+		}
+	}
+}
 
-		if configInterfaceTracking {
-			configInterface = append(configInterface, element)
+func parseInterfaces(sections []fortiosSection) *[]model.FortigateInterface {
+
+	var deviceInterfaces []model.FortigateInterface
+
+	for _, section := range sections {
+		for _, entry := range section.config.entries {
+			count := len(deviceInterfaces)
+			parseSingleInterface(entry, &deviceInterfaces)
+			if len(deviceInterfaces) > count && deviceInterfaces[count].Vdom == "" {
+				deviceInterfaces[count].Vdom = section.vdom
+			}
 		}
 	}
 
 	return &deviceInterfaces
 }
 
-func getElementValue(element string, filter string) string {
-	return strings.ReplaceAll(strings.ReplaceAll(element, filter, ""), "\"", "")
-}
-
 func convertVirtualSwitch(virtutalSwitches *[]model.FortigateVirtualSwitch, deviceInterfaces *[]model.FortigateInterface ) {
 
 	var virtualSwitchNames = map[string]string{}
 
+	var virtualSwitchVlans = map[string]string{}
+
 	for _, member := range *virtutalSwitches {
-		var vswitch model.FortigateInterface
-		vswitch.Name = member.Name
+		// a switch interface that is also configured as interface keeps its settings
+		index := slices.IndexFunc(*deviceInterfaces, func(iface model.FortigateInterface) bool { return iface.Name == member.Name })
+		if index == -1 {
+			*deviceInterfaces = append(*deviceInterfaces, model.FortigateInterface{Name: member.Name})
+			index = len(*deviceInterfaces) - 1
+		}
+		vswitch := &(*deviceInterfaces)[index]
 		vswitch.InterfaceType = "virtual-switch"
-		vswitch.Description = "virtual-switch"
+		if vswitch.Description == "" {
+			vswitch.Description = "virtual-switch"
+		}
 		vswitch.Members = member.Members
-		*deviceInterfaces = append(*deviceInterfaces, vswitch)
+		if member.VlanId != "" {
+			vswitch.VlanMode = vlanModeAccess
+			vswitch.VlanId = member.VlanId
+		}
 		for _, vswitchMember := range member.Members {
 			virtualSwitchNames[vswitchMember] = member.Name
+			virtualSwitchVlans[vswitchMember] = member.VlanId
 		} 
 	}
 
 	for index, dinterface := range *deviceInterfaces {
-		if virtualSwitchNames[dinterface.Name] != "" {
+		if virtualSwitchNames[dinterface.Name] != "" && dinterface.Parent == "" {
 			(*deviceInterfaces)[index].Parent = virtualSwitchNames[dinterface.Name]
 		}
+		if virtualSwitchVlans[dinterface.Name] != "" && dinterface.VlanMode == "" {
+			(*deviceInterfaces)[index].VlanMode = vlanModeAccess
+			(*deviceInterfaces)[index].VlanId = virtualSwitchVlans[dinterface.Name]
+		}
 	}
 }
 
-func parseSingleInterface(interfaceData []string, results *[]model.FortigateInterface) {
-
-	var name, interfaceType, vlanId, parentName, alias, vdom, ip, speed, member, status, description string
-
-	prefixes := map[string]*string{
-		interfaceNamePrefix:            &name,
-		interfaceTypePrefix:            &interfaceType,
-		interfaceVlanIdPrefix:          &vlanId,
-		interfaceParentInterfacePrefix: &parentName,
-		interfaceAliasPrefix:           &alias,
-		interfaceVdomPrefix:            &vdom,
-		interfaceIp:                    &ip,
-		interfaceSpeed:                 &speed,
-		intefaceMember:                 &member,
-		interfaceStatus:                &status,
-		interfaceDescription:           &description,
-	}
-
-	for _, element := range interfaceData {
-		for prefix, value := range prefixes {
-			if strings.HasPrefix(element, prefix) {
-				*value = getElementValue(element, prefix)
-			}
-		}
+func parseSingleInterface(interfaceData *fortiosNode, results *[]model.FortigateInterface) {
+
+	name := interfaceData.name
+	interfaceType := interfaceData.value("type")
+	vlanId := interfaceData.value("vlanid")
+	parentName := interfaceData.value("interface")
+	alias := interfaceData.value("alias")
+	vdom := interfaceData.value("vdom")
+	speed := interfaceData.value("speed")
+	status := interfaceData.value("status")
+	description := interfaceData.value("description")
+	ipAddresses := fortiosAddresses(interfaceData)
+	fhrpGroups := fortiosVrrpGroups(interfaceData)
+	vrf := interfaceData.value("vrf")
+	// vrf 0 is the default routing table
+	if vrf == "0" {
+		vrf = ""
 	}
 
 	if name == "''" {
@@ -230,8 +316,7 @@ func parseSingleInterface(interfaceData []string, results *[]model.FortigateInte
 		var aggr model.FortigateInterface
 		aggr.InterfaceType = "aggregate"
 		aggr.Name = name
-		memberNames := strings.Split(member, " ")
-		for _, memberName := range memberNames {
+		for _, memberName := range interfaceData.values("member") {
 			if memberName != "" && memberName != "''" {
 				aggr.Members = append(aggr.Members, memberName)
 			}
@@ -241,6 +326,10 @@ func parseSingleInterface(interfaceData []string, results *[]model.FortigateInte
 			aggr.Description = "redundant; " + aggr.Description
 		}
 		aggr.Status = status
+		aggr.Vdom = vdom
+		aggr.IPAddresses = ipAddresses
+		aggr.FhrpGroups = fhrpGroups
+		aggr.Vrf = vrf
 		*results = append(*results, aggr)
 	case "physical":
 		var pyh model.FortigateInterface
@@ -248,22 +337,129 @@ func parseSingleInterface(interfaceData []string, results *[]model.FortigateInte
 		pyh.Name = name
 		pyh.Speed = speed
 		pyh.Status = status
+		pyh.Vdom = vdom
+		pyh.IPAddresses = ipAddresses
+		pyh.FhrpGroups = fhrpGroups
+		pyh.Vrf = vrf
 		pyh.Description = createDescription(alias, vdom, description)
+		if interfaceData.value("trunk") == "enable" {
+			pyh.VlanMode = vlanModeTagged
+		}
 		*results = append(*results, pyh)
+	case "hard-switch", "switch":
+		// the members are set by the virtual-switch or switch-interface config
+		var vswitch model.FortigateInterface
+		vswitch.InterfaceType = "virtual-switch"
+		vswitch.Name = name
+		vswitch.Status = status
+		vswitch.Vdom = vdom
+		vswitch.IPAddresses = ipAddresses
+		vswitch.FhrpGroups = fhrpGroups
+		vswitch.Vrf = vrf
+		vswitch.Description = createDescription(alias, vdom, description)
+		*results = append(*results, vswitch)
 	case "vlan":
-		*results = append(*results, createVlan(name, alias, vdom, vlanId, parentName, description))
-	case "loopback":
-		slog.Warn("loopback interface; todo")
+		vid := createVlan(name, alias, vdom, vlanId, parentName, description)
+		vid.IPAddresses = ipAddresses
+		vid.FhrpGroups = fhrpGroups
+		vid.Vrf = vrf
+		*results = append(*results, vid)
+	case "loopback", "tunnel", "vxlan":
+		var virt model.FortigateInterface
+		virt.InterfaceType = "virtual"
+		virt.Name = name
+		virt.Parent = parentName
+		virt.Status = status
+		virt.Vdom = vdom
+		virt.IPAddresses = ipAddresses
+		virt.Vrf = vrf
+		virt.Description = createDescription(alias, vdom, description)
+		*results = append(*results, virt)
 	case "":
 		if vlanId != "" {
-			*results = append(*results, createVlan(name, alias, vdom, vlanId, parentName, description))
+			vid := createVlan(name, alias, vdom, vlanId, parentName, description)
+			vid.IPAddresses = ipAddresses
+			vid.FhrpGroups = fhrpGroups
+			vid.Vrf = vrf
+			*results = append(*results, vid)
 		}
 	}
 }
 
+// fortiosAddresses returns the ip, secondary ips and ipv6 addresses of an interface in prefix notation.
+func fortiosAddresses(interfaceData *fortiosNode) []string {
+	var result []string
+
+	addIPv4 := func(values []string) {
+		if len(values) == 1 && strings.Contains(values[0], "/") {
+			values = strings.Split(values[0], "/")
+		}
+		if len(values) != 2 || values[0] == "0.0.0.0" {
+			return
+		}
+		if address := maskToCIDR(values[0], values[1]); address != "" {
+			result = append(result, address)
+		}
+	}
+
+	addIPv4(interfaceData.values("ip"))
+	if secondaryIps := interfaceData.config("secondaryip"); secondaryIps != nil {
+		for _, secondaryIp := range secondaryIps.entries {
+			addIPv4(secondaryIp.values("ip"))
+		}
+	}
+
+	if ipv6 := interfaceData.config("ipv6"); ipv6 != nil {
+		if address := ipv6.value("ip6-address"); address != "" && !strings.HasPrefix(address, "::/") {
+			result = append(result, address)
+		}
+		if extraAddresses := ipv6.config("ip6-extra-addr"); extraAddresses != nil {
+			for _, extraAddress := range extraAddresses.entries {
+				result = append(result, extraAddress.name)
+			}
+		}
+	}
+
+	return result
+}
+
+// fortiosVrrpGroups returns the vrrp groups of the interface, from "config vrrp"
+// and "config vrrp6" in the ipv6 block.
+func fortiosVrrpGroups(interfaceData *fortiosNode) []model.FhrpGroup {
+	var result []model.FhrpGroup
+
+	addGroups := func(vrrp *fortiosNode, vipKey string, protocol string) {
+		if vrrp == nil {
+			return
+		}
+		for _, entry := range vrrp.entries {
+			vip := entry.value(vipKey)
+			if vip == "" || vip == "0.0.0.0" || vip == "::" {
+				continue
+			}
+			group := model.FhrpGroup{Protocol: protocol, GroupId: entry.value("vrid"), VirtualIPs: []string{vip}, Priority: entry.value("priority")}
+			if group.GroupId == "" {
+				group.GroupId = entry.name
+			}
+			if entry.value("version") == "3" {
+				group.Protocol = fhrpVrrp3
+			}
+			result = append(result, group)
+		}
+	}
+
+	addGroups(interfaceData.config("vrrp"), "vrip", fhrpVrrp2)
+	if ipv6 := interfaceData.config("ipv6"); ipv6 != nil {
+		addGroups(ipv6.config("vrrp6"), "vrip6", fhrpVrrp3)
+	}
+
+	return result
+}
+
 func createVlan(name string, alias string, vdom string, vlanId string, parentName string, description string) model.FortigateInterface {
 	var vid model.FortigateInterface
 	vid.InterfaceType = "vlan"
+	vid.ObjectName = name
 	if alias != "" {
 		vid.Name = alias
 	} else {
@@ -272,6 +468,7 @@ func createVlan(name string, alias string, vdom string, vlanId string, parentNam
 	vid.Description = createDescription(alias, vdom, description)
 	vid.VlanId = vlanId
 	vid.Parent = parentName
+	vid.Vdom = vdom
 	return vid
 }
 
@@ -0,0 +1,101 @@
+package configparser
+
+import "testing"
+
+// vlans with an alias are synced with the alias, the config keeps referencing
+// them with their name.
+const fortiosAliasFixture = `config system interface
+    edit "port1"
+        set vdom "root"
+        set type physical
+    next
+    edit "port2"
+        set vdom "root"
+        set type physical
+    next
+    edit "vlan100"
+        set vdom "root"
+        set alias "servers"
+        set interface "port1"
+        set vlanid 100
+    next
+    edit "vlan200"
+        set vdom "root"
+        set alias "internet"
+        set ip 203.0.113.2 255.255.255.0
+        set interface "port2"
+        set vlanid 200
+    next
+end
+config vpn ipsec phase1-interface
+    edit "to-branch"
+        set interface "vlan200"
+        set remote-gw 198.51.100.1
+    next
+end
+config system sdwan
+    config members
+        edit 1
+            set interface "vlan200"
+            set zone "internet"
+            set gateway 203.0.113.1
+            set priority 10
+        next
+    end
+end
+config system zone
+    edit "lan"
+        set interface "vlan100" "port2"
+    next
+end
+`
+
+func TestParseFortiOSConfigZones(t *testing.T) {
+	config := fortiosAliasFixture
+	interfaces, err := ParseFortiOSConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vlan := findInterface(t, interfaces, "servers")
+	if vlan.ObjectName != "vlan100" || vlan.Zone != "lan" {
+		t.Errorf("servers: got object name %q zone %q, want vlan100 lan", vlan.ObjectName, vlan.Zone)
+	}
+	for name, zone := range map[string]string{"port1": "", "port2": "lan"} {
+		if iface := findInterface(t, interfaces, name); iface.Zone != zone {
+			t.Errorf("%s: got zone %q, want %q", name, iface.Zone, zone)
+		}
+	}
+}
+
+func TestParseFortiOSConfigTunnelUnderlay(t *testing.T) {
+	config := fortiosAliasFixture
+	interfaces, err := ParseFortiOSConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tunnel := findInterface(t, interfaces, "to-branch")
+	if tunnel.Parent != "internet" || tunnel.Tunnel == nil {
+		t.Fatalf("to-branch: got parent %q tunnel %v, want internet", tunnel.Parent, tunnel.Tunnel)
+	}
+	if tunnel.Tunnel.LocalGateway != "203.0.113.2" || tunnel.Tunnel.RemoteGateway != "198.51.100.1" {
+		t.Errorf("to-branch: got local %q remote %q, want 203.0.113.2 198.51.100.1", tunnel.Tunnel.LocalGateway, tunnel.Tunnel.RemoteGateway)
+	}
+}
+
+func TestParseFortiOSConfigSdwan(t *testing.T) {
+	config := fortiosAliasFixture
+	interfaces, err := ParseFortiOSConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vlan := findInterface(t, interfaces, "internet")
+	if vlan.SdwanZone != "internet" || vlan.SdwanGateway != "203.0.113.1" || vlan.SdwanPriority != "10" {
+		t.Errorf("internet: got sdwan zone %q gateway %q priority %q, want internet 203.0.113.1 10", vlan.SdwanZone, vlan.SdwanGateway, vlan.SdwanPriority)
+	}
+	if vlan.Description != "vdom: root; alias: internet" {
+		t.Errorf("internet: got description %q", vlan.Description)
+	}
+}
@@ -0,0 +1,194 @@
+package configparser
+
+import (
+	"strings"
+)
+
+const (
+	fortiosConfig = "config"
+	fortiosEdit   = "edit"
+	fortiosSet    = "set"
+	fortiosUnset  = "unset"
+	fortiosNext   = "next"
+	fortiosEnd    = "end"
+	fortiosVdom   = "vdom"
+	fortiosGlobal = "global"
+)
+
+// fortiosNode is a "config" block or an "edit" entry of a FortiOS config. The
+// root node only holds the top level config blocks.
+type fortiosNode struct {
+	name     string
+	isEntry  bool
+	settings map[string][]string
+	configs  []*fortiosNode
+	entries  []*fortiosNode
+}
+
+// fortiosSection is a config block together with the vdom it belongs to, the
+// vdom is empty for configs without vdoms or in "config global".
+type fortiosSection struct {
+	vdom   string
+	config *fortiosNode
+}
+
+func newFortiOSNode(name string, isEntry bool) *fortiosNode {
+	return &fortiosNode{name: name, isEntry: isEntry, settings: map[string][]string{}}
+}
+
+// parseFortiOSTree parses the config/edit/set/unset/next/end structure of a
+// FortiOS config into a tree, the indentation is not used.
+func parseFortiOSTree(config *string) *fortiosNode {
+	root := newFortiOSNode("", false)
+	stack := []*fortiosNode{root}
+
+	for _, statement := range tokenizeFortiOSConfig(config) {
+		current := stack[len(stack)-1]
+
+		switch statement[0] {
+		case fortiosConfig:
+			node := newFortiOSNode(strings.Join(statement[1:], " "), false)
+			current.configs = append(current.configs, node)
+			stack = append(stack, node)
+		case fortiosEdit:
+			if len(statement) < 2 || current.isEntry {
+				continue
+			}
+			node := newFortiOSNode(statement[1], true)
+			current.entries = append(current.entries, node)
+			stack = append(stack, node)
+		case fortiosSet:
+			if len(statement) >= 2 {
+				current.settings[statement[1]] = statement[2:]
+			}
+		case fortiosUnset:
+			if len(statement) >= 2 {
+				delete(current.settings, statement[1])
+			}
+		case fortiosNext:
+			if current.isEntry {
+				stack = stack[:len(stack)-1]
+			}
+		case fortiosEnd:
+			// a missing "next" is closed together with its config block
+			if current.isEntry && len(stack) > 2 {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	return root
+}
+
+// tokenizeFortiOSConfig splits the config into statements of tokens. Quoted
+// values are a single token and can contain spaces, escaped quotes and newlines.
+func tokenizeFortiOSConfig(config *string) [][]string {
+	var (
+		statements [][]string
+		tokens     []string
+		current    strings.Builder
+		inQuotes   bool
+		escaped    bool
+		quoted     bool
+		comment    bool
+	)
+
+	flush := func() {
+		if current.Len() > 0 || quoted {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+		quoted = false
+	}
+
+	for _, char := range *config {
+		switch {
+		case comment:
+			if char == '\n' {
+				comment = false
+			}
+		case escaped:
+			current.WriteRune(char)
+			escaped = false
+		case inQuotes && char == '\\':
+			escaped = true
+		case inQuotes && char == '"':
+			inQuotes = false
+		case inQuotes:
+			current.WriteRune(char)
+		case char == '"':
+			inQuotes = true
+			quoted = true
+		case char == '#' && len(tokens) == 0 && current.Len() == 0:
+			comment = true
+		case char == '\n':
+			flush()
+			if len(tokens) > 0 {
+				statements = append(statements, tokens)
+				tokens = nil
+			}
+		case char == ' ' || char == '\t' || char == '\r':
+			flush()
+		default:
+			current.WriteRune(char)
+		}
+	}
+	flush()
+	if len(tokens) > 0 {
+		statements = append(statements, tokens)
+	}
+
+	return statements
+}
+
+// sections returns every config block with the given name, like "system interface",
+// found at the top level, in "config global" and in each "config vdom" entry.
+func (node *fortiosNode) sections(name string) []fortiosSection {
+	var result []fortiosSection
+
+	for _, config := range node.configs {
+		switch config.name {
+		case name:
+			result = append(result, fortiosSection{config: config})
+		case fortiosGlobal:
+			for _, child := range config.configs {
+				if child.name == name {
+					result = append(result, fortiosSection{config: child})
+				}
+			}
+		case fortiosVdom:
+			for _, vdom := range config.entries {
+				for _, child := range vdom.configs {
+					if child.name == name {
+						result = append(result, fortiosSection{vdom: vdom.name, config: child})
+					}
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// config returns the nested config block with the given name.
+func (node *fortiosNode) config(name string) *fortiosNode {
+	for _, config := range node.configs {
+		if config.name == name {
+			return config
+		}
+	}
+	return nil
+}
+
+// value returns the value of a set line, multiple values are joined with a space.
+func (node *fortiosNode) value(key string) string {
+	return strings.Join(node.settings[key], " ")
+}
+
+// values returns the separate values of a set line like `set member "port1" "port2"`.
+func (node *fortiosNode) values(key string) []string {
+	return node.settings[key]
+}
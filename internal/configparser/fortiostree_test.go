@@ -0,0 +1,119 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenizeFortiOSConfig(t *testing.T) {
+	tests := []struct {
+		config string
+		want   [][]string
+	}{
+		{`set alias "wan link"`, [][]string{{"set", "alias", "wan link"}}},
+		{`set member "port1" "port2"`, [][]string{{"set", "member", "port1", "port2"}}},
+		{`set description ""`, [][]string{{"set", "description", ""}}},
+		{`set comments "say \"hi\""`, [][]string{{"set", "comments", `say "hi"`}}},
+		{"set comments \"line 1\nline 2\"\nnext", [][]string{{"set", "comments", "line 1\nline 2"}, {"next"}}},
+		{"#config-version=FGT60F\n\n    edit \"port1\"\r\n", [][]string{{"edit", "port1"}}},
+		{`set alias "#1"`, [][]string{{"set", "alias", "#1"}}},
+	}
+	for _, tt := range tests {
+		config := tt.config
+		if got := tokenizeFortiOSConfig(&config); !equalStatements(got, tt.want) {
+			t.Errorf("tokenizeFortiOSConfig(%q) = %q, want %q", tt.config, got, tt.want)
+		}
+	}
+}
+
+const fortiosVdomFixture = `config vdom
+edit root
+next
+edit customer
+next
+end
+config global
+config system interface
+    edit "port1"
+        set vdom "root"
+        set ip 192.0.2.1 255.255.255.0
+        set alias "uplink"
+        unset alias
+    next
+    edit "port2"
+        set vdom "customer"
+        config ipv6
+            set ip6-address 2001:db8::1/64
+        end
+end
+end
+config vdom
+edit root
+config system zone
+    edit "lan"
+        set interface "port1"
+    next
+end
+next
+edit customer
+config system zone
+    edit "dmz"
+        set interface "port2"
+    next
+end
+next
+end
+`
+
+func TestParseFortiOSTree(t *testing.T) {
+	config := fortiosVdomFixture
+	root := parseFortiOSTree(&config)
+
+	interfaces := root.sections("system interface")
+	if len(interfaces) != 1 || interfaces[0].vdom != "" {
+		t.Fatalf("sections(system interface) = %d sections, want 1 global section", len(interfaces))
+	}
+
+	entries := interfaces[0].config.entries
+	if len(entries) != 2 || entries[0].name != "port1" || entries[1].name != "port2" {
+		t.Fatalf("system interface entries = %d, want port1 and port2", len(entries))
+	}
+	if got := entries[0].value("ip"); got != "192.0.2.1 255.255.255.0" {
+		t.Errorf("port1 ip = %q", got)
+	}
+	if _, ok := entries[0].settings["alias"]; ok {
+		t.Errorf("port1 alias is not unset")
+	}
+	// the missing "next" of port2 is closed together with its config block
+	if ipv6 := entries[1].config("ipv6"); ipv6 == nil || ipv6.value("ip6-address") != "2001:db8::1/64" {
+		t.Errorf("port2 ipv6 config not parsed")
+	}
+
+	zones := root.sections("system zone")
+	var got []string
+	for _, section := range zones {
+		for _, zone := range section.config.entries {
+			got = append(got, section.vdom+"/"+zone.name+"/"+zone.value("interface"))
+		}
+	}
+	if want := []string{"root/lan/port1", "customer/dmz/port2"}; !slices.Equal(got, want) {
+		t.Errorf("zones = %v, want %v", got, want)
+	}
+}
+
+func TestParseFortiOSTreeWithoutVdoms(t *testing.T) {
+	config := `config system interface
+    edit "port1"
+        set type physical
+    next
+end
+`
+	root := parseFortiOSTree(&config)
+	sections := root.sections("system interface")
+	if len(sections) != 1 || sections[0].vdom != "" || len(sections[0].config.entries) != 1 {
+		t.Fatalf("sections(system interface) = %+v, want one section with port1", sections)
+	}
+	if got := sections[0].config.entries[0].value("type"); got != "physical" {
+		t.Errorf("port1 type = %q, want physical", got)
+	}
+}
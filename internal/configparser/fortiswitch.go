@@ -0,0 +1,84 @@
+package configparser
+
+import (
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+// ParseFortiSwitchConfig returns the ports of the FortiSwitches managed by a FortiGate,
+// from "config switch-controller managed-switch". The switch vlans are FortiGate
+// interfaces, so the vlan ids are looked up in "config system interface".
+func ParseFortiSwitchConfig(config *string) (*[]model.ManagedSwitch, error) {
+	const (
+		sectionInterface     = "system interface"
+		sectionManagedSwitch = "switch-controller managed-switch"
+	)
+
+	tree := parseFortiOSTree(config)
+
+	vlanIds := map[string]string{}
+	for _, section := range tree.sections(sectionInterface) {
+		for _, entry := range section.config.entries {
+			if vlanId := entry.value("vlanid"); vlanId != "" {
+				vlanIds[entry.name] = vlanId
+			}
+		}
+	}
+
+	var managedSwitches []model.ManagedSwitch
+	for _, section := range tree.sections(sectionManagedSwitch) {
+		for _, entry := range section.config.entries {
+			managedSwitch := model.ManagedSwitch{Serial: entry.name, Name: entry.value("name")}
+			if ports := entry.config("ports"); ports != nil {
+				for _, port := range ports.entries {
+					managedSwitch.Interfaces = append(managedSwitch.Interfaces, parseFortiSwitchPort(port, vlanIds))
+				}
+			}
+			managedSwitches = append(managedSwitches, managedSwitch)
+		}
+	}
+
+	return &managedSwitches, nil
+}
+
+func parseFortiSwitchPort(port *fortiosNode, vlanIds map[string]string) model.FortigateInterface {
+	var iface model.FortigateInterface
+	iface.Name = port.name
+	iface.Description = port.value("description")
+	iface.Status = port.value("status")
+
+	if port.value("type") == "trunk" {
+		iface.InterfaceType = "aggregate"
+		iface.Members = port.values("members")
+		if mode := port.value("mode"); mode != "" {
+			createDescriptionBuilder(mode, "mode", &iface.Description)
+		}
+	} else {
+		iface.InterfaceType = "physical"
+	}
+
+	// ports without a vlan are lag members or not in use
+	nativeVlan := vlanIds[port.value("vlan")]
+	if nativeVlan == "" {
+		return iface
+	}
+	iface.VlanId = nativeVlan
+
+	var allowedVlans []string
+	for _, vlan := range port.values("allowed-vlans") {
+		if vlanId := vlanIds[vlan]; vlanId != "" && vlanId != nativeVlan {
+			allowedVlans = append(allowedVlans, vlanId)
+		}
+	}
+
+	switch {
+	case port.value("allowed-vlans-all") == "enable":
+		iface.VlanMode = vlanModeTaggedAll
+	case len(allowedVlans) > 0:
+		iface.VlanMode = vlanModeTagged
+		iface.TaggedVlans = allowedVlans
+	default:
+		iface.VlanMode = vlanModeAccess
+	}
+
+	return iface
+}
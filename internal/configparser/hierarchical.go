@@ -0,0 +1,209 @@
+package configparser
+
+import (
+	"bufio"
+	"strings"
+)
+
+// parseHierarchicalConfig flattens a curly-brace config (Junos, VyOS) or its
+// "display set" form into one token path per statement, so both formats can
+// be queried the same way.
+func parseHierarchicalConfig(config *string) [][]string {
+	if isSetConfig(config) {
+		return parseSetStatements(config)
+	}
+	return parseBraceStatements(config)
+}
+
+func isSetConfig(config *string) bool {
+	scanner := bufio.NewScanner(strings.NewReader(*config))
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "set ") {
+			return true
+		}
+	}
+	return false
+}
+
+func parseSetStatements(config *string) [][]string {
+	var statements [][]string
+
+	scanner := bufio.NewScanner(strings.NewReader(*config))
+	for scanner.Scan() {
+		tokens := tokenizeHierarchicalLine(scanner.Text())
+		if len(tokens) < 2 || tokens[0] != "set" {
+			continue
+		}
+		statements = append(statements, tokens[1:])
+	}
+
+	return statements
+}
+
+func parseBraceStatements(config *string) [][]string {
+	var (
+		statements [][]string
+		path       [][]string
+		opened     []int
+		inComment  bool
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(*config))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if inComment {
+			_, rest, closed := strings.Cut(line, "*/")
+			if !closed {
+				continue
+			}
+			inComment = false
+			line = strings.TrimSpace(rest)
+		}
+		if before, _, opened := strings.Cut(line, "/*"); opened {
+			if !strings.Contains(line, "*/") {
+				inComment = true
+			}
+			line = strings.TrimSpace(before)
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		tokens := tokenizeHierarchicalLine(line)
+		if len(tokens) > 0 && (tokens[0] == "inactive:" || tokens[0] == "protect:") {
+			tokens = tokens[1:]
+		}
+
+		var current []string
+		for _, token := range tokens {
+			switch token {
+			case "{":
+				path = append(path, current)
+				opened = append(opened, len(statements))
+				current = nil
+			case "}":
+				if len(path) > 0 {
+					// an empty block is a statement on its own, like "interface eth1 { }" in VyOS
+					if opened[len(opened)-1] == len(statements) {
+						statements = append(statements, expandStatement(path, nil)...)
+					}
+					path = path[:len(path)-1]
+					opened = opened[:len(opened)-1]
+				}
+				current = nil
+			case ";":
+				statements = append(statements, expandStatement(path, current)...)
+				current = nil
+			default:
+				current = append(current, token)
+			}
+		}
+		// VyOS style configs end statements at the end of the line instead of a ';'
+		if len(current) > 0 {
+			statements = append(statements, expandStatement(path, current)...)
+		}
+	}
+
+	return statements
+}
+
+// expandStatement prefixes the statement with its parent path and expands
+// "[ a b ]" lists into one statement per value.
+func expandStatement(path [][]string, tokens []string) [][]string {
+	var prefix []string
+	for _, element := range path {
+		prefix = append(prefix, element...)
+	}
+
+	var values []string
+	inList := false
+	for _, token := range tokens {
+		switch {
+		case token == "[":
+			inList = true
+		case token == "]":
+			inList = false
+		case inList:
+			values = append(values, token)
+		default:
+			prefix = append(prefix, token)
+		}
+	}
+
+	if len(values) == 0 {
+		return [][]string{prefix}
+	}
+
+	var result [][]string
+	for _, value := range values {
+		statement := append([]string{}, prefix...)
+		result = append(result, append(statement, value))
+	}
+	return result
+}
+
+// tokenizeHierarchicalLine splits a line on whitespace, keeping quoted strings
+// together and returning braces, brackets and semicolons as separate tokens.
+func tokenizeHierarchicalLine(line string) []string {
+	var (
+		tokens   []string
+		current  strings.Builder
+		inQuotes bool
+		quote    rune
+	)
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, char := range line {
+		switch {
+		case inQuotes && char == quote:
+			inQuotes = false
+			tokens = append(tokens, current.String())
+			current.Reset()
+		case inQuotes:
+			current.WriteRune(char)
+		case char == '"' || char == '\'':
+			flush()
+			inQuotes = true
+			quote = char
+		case char == ' ' || char == '\t':
+			flush()
+		case char == '{' || char == '}' || char == ';' || char == '[' || char == ']':
+			flush()
+			tokens = append(tokens, string(char))
+		default:
+			current.WriteRune(char)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// statementsWithPrefix returns the remaining tokens of every statement that
+// starts with the given tokens.
+func statementsWithPrefix(statements [][]string, prefix ...string) [][]string {
+	var result [][]string
+	for _, statement := range statements {
+		if len(statement) < len(prefix) {
+			continue
+		}
+		matches := true
+		for index, token := range prefix {
+			if statement[index] != token {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			result = append(result, statement[len(prefix):])
+		}
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+)
+
+func equalStatements(a [][]string, b [][]string) bool {
+	return slices.EqualFunc(a, b, func(x []string, y []string) bool { return slices.Equal(x, y) })
+}
+
+func TestTokenizeHierarchicalLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", nil},
+		{"description uplink;", []string{"description", "uplink", ";"}},
+		{`description "to core 1";`, []string{"description", "to core 1", ";"}},
+		{"description 'to core 2';", []string{"description", "to core 2", ";"}},
+		{"ge-0/0/1 {", []string{"ge-0/0/1", "{"}},
+		{"members [ 10 20 ];", []string{"members", "[", "10", "20", "]", ";"}},
+		{"unit 0{family inet;}", []string{"unit", "0", "{", "family", "inet", ";", "}"}},
+		{"\taddress\t10.0.0.1/24", []string{"address", "10.0.0.1/24"}},
+		{`description ""`, []string{"description", ""}},
+	}
+	for _, tt := range tests {
+		if got := tokenizeHierarchicalLine(tt.line); !slices.Equal(got, tt.want) {
+			t.Errorf("tokenizeHierarchicalLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseBraceStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   [][]string
+	}{
+		{
+			name: "junos",
+			config: `interfaces {
+    /* uplink */
+    ge-0/0/1 {
+        description "to core";
+        unit 0 {
+            family ethernet-switching {
+                vlan {
+                    members [ 10 20 ];
+                }
+            }
+        }
+    }
+    inactive: ge-0/0/2 {
+        disable;
+    }
+}`,
+			want: [][]string{
+				{"interfaces", "ge-0/0/1", "description", "to core"},
+				{"interfaces", "ge-0/0/1", "unit", "0", "family", "ethernet-switching", "vlan", "members", "10"},
+				{"interfaces", "ge-0/0/1", "unit", "0", "family", "ethernet-switching", "vlan", "members", "20"},
+				{"interfaces", "ge-0/0/2", "disable"},
+			},
+		},
+		{
+			name: "vyos",
+			config: `interfaces {
+    ethernet eth0 {
+        address 192.0.2.1/24
+        description "wan"
+    }
+    ethernet eth1 {
+    }
+}
+# comment`,
+			want: [][]string{
+				{"interfaces", "ethernet", "eth0", "address", "192.0.2.1/24"},
+				{"interfaces", "ethernet", "eth0", "description", "wan"},
+				{"interfaces", "ethernet", "eth1"},
+			},
+		},
+		{
+			name: "multi-line comment",
+			config: `system {
+    /* first
+       second */
+    host-name fw1;
+}`,
+			want: [][]string{{"system", "host-name", "fw1"}},
+		},
+	}
+	for _, tt := range tests {
+		config := tt.config
+		if got := parseBraceStatements(&config); !equalStatements(got, tt.want) {
+			t.Errorf("%s: parseBraceStatements() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseHierarchicalConfigSet(t *testing.T) {
+	config := `## Last commit
+set interfaces ge-0/0/1 description "to core"
+set interfaces ge-0/0/1 unit 0 family inet address 192.0.2.1/24
+delete interfaces ge-0/0/2`
+	want := [][]string{
+		{"interfaces", "ge-0/0/1", "description", "to core"},
+		{"interfaces", "ge-0/0/1", "unit", "0", "family", "inet", "address", "192.0.2.1/24"},
+	}
+	if got := parseHierarchicalConfig(&config); !equalStatements(got, want) {
+		t.Errorf("parseHierarchicalConfig() = %q, want %q", got, want)
+	}
+}
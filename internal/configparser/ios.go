@@ -0,0 +1,140 @@
+package configparser
+
+import (
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	iosInterfacePrefix     = "interface "
+	iosDescription         = "description "
+	iosChannelGroup        = "channel-group "
+	iosEncapsulation       = "encapsulation dot1Q "
+	iosSwitchportMode      = "switchport mode "
+	iosAccessVlan          = "switchport access vlan "
+	iosNativeVlan          = "switchport trunk native vlan "
+	iosTrunkAllowedVlan    = "switchport trunk allowed vlan "
+	iosShutdown            = "shutdown"
+	iosPortChannelName     = "Port-channel"
+	iosVlanInterfacePrefix = "Vlan"
+	iosVrfForwarding       = "vrf forwarding "
+	iosIPVrfForwarding     = "ip vrf forwarding "
+	iosIPAddress           = "ip address "
+	iosIPv6Address         = "ipv6 address "
+	iosStandby             = "standby "
+	iosVrrp                = "vrrp "
+)
+
+func ParseIOSConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+	channelGroups := map[string][]string{}
+
+	for _, section := range parseIndentedSections(config, iosInterfacePrefix) {
+		iface, channelGroup := parseIOSInterface(section)
+		if iface.Name == "" {
+			continue
+		}
+		if channelGroup != "" {
+			lagName := iosPortChannelName + channelGroup
+			channelGroups[lagName] = append(channelGroups[lagName], iface.Name)
+		}
+		deviceInterfaces = append(deviceInterfaces, iface)
+	}
+
+	addLagMembers(&deviceInterfaces, channelGroups)
+
+	return &deviceInterfaces, nil
+}
+
+func parseIOSInterface(section configSection) (model.FortigateInterface, string) {
+	var iface model.FortigateInterface
+	var channelGroup, accessVlan, nativeVlan string
+	var allowedVlans []string
+	allowAll := true
+
+	fields := strings.Fields(section.header)
+	if len(fields) == 0 {
+		return iface, ""
+	}
+	iface.Name = fields[0]
+
+	for _, line := range section.lines {
+		switch {
+		case strings.HasPrefix(line, iosDescription):
+			iface.Description = strings.TrimPrefix(line, iosDescription)
+		case line == iosShutdown:
+			iface.Status = "down"
+		case strings.HasPrefix(line, iosChannelGroup):
+			channelGroup = strings.Fields(strings.TrimPrefix(line, iosChannelGroup))[0]
+		case strings.HasPrefix(line, iosEncapsulation):
+			iface.VlanId = strings.Fields(strings.TrimPrefix(line, iosEncapsulation))[0]
+		case strings.HasPrefix(line, iosSwitchportMode):
+			switch strings.TrimPrefix(line, iosSwitchportMode) {
+			case "access":
+				iface.VlanMode = vlanModeAccess
+			case "trunk":
+				iface.VlanMode = vlanModeTagged
+			}
+		case strings.HasPrefix(line, iosAccessVlan):
+			accessVlan = strings.TrimPrefix(line, iosAccessVlan)
+		case strings.HasPrefix(line, iosNativeVlan):
+			nativeVlan = strings.TrimPrefix(line, iosNativeVlan)
+		case strings.HasPrefix(line, iosTrunkAllowedVlan):
+			allowedVlans, allowAll = applyVlanListCommand(allowedVlans, strings.TrimPrefix(line, iosTrunkAllowedVlan))
+		case strings.HasPrefix(line, iosVrfForwarding):
+			iface.Vrf = strings.TrimPrefix(line, iosVrfForwarding)
+		case strings.HasPrefix(line, iosIPVrfForwarding):
+			iface.Vrf = strings.TrimPrefix(line, iosIPVrfForwarding)
+		case strings.HasPrefix(line, iosIPAddress):
+			// ip address 10.0.0.1 255.255.255.0 [secondary]
+			addressFields := strings.Fields(strings.TrimPrefix(line, iosIPAddress))
+			if len(addressFields) >= 2 {
+				if address := maskToCIDR(addressFields[0], addressFields[1]); address != "" {
+					iface.IPAddresses = append(iface.IPAddresses, address)
+				}
+			}
+		case strings.HasPrefix(line, iosIPv6Address):
+			addressFields := strings.Fields(strings.TrimPrefix(line, iosIPv6Address))
+			if len(addressFields) > 0 && strings.Contains(addressFields[0], "/") {
+				iface.IPAddresses = append(iface.IPAddresses, addressFields[0])
+			}
+		case strings.HasPrefix(line, iosStandby):
+			applyFhrpCommand(&iface.FhrpGroups, fhrpHsrp, strings.TrimPrefix(line, iosStandby))
+		case strings.HasPrefix(line, iosVrrp):
+			applyFhrpCommand(&iface.FhrpGroups, fhrpVrrp2, strings.TrimPrefix(line, iosVrrp))
+		}
+	}
+
+	switch iface.VlanMode {
+	case vlanModeAccess:
+		iface.VlanId = accessVlan
+	case vlanModeTagged:
+		iface.VlanId = nativeVlan
+		iface.VlanMode = trunkVlanMode(allowAll)
+		iface.TaggedVlans = allowedVlans
+	}
+
+	baseName, _, isSubinterface := strings.Cut(iface.Name, ".")
+
+	switch {
+	case strings.HasPrefix(iface.Name, "Null"):
+		iface.Name = ""
+	case isSubinterface:
+		iface.InterfaceType = "vlan"
+		iface.Parent = baseName
+		iface.VlanMode = ""
+		iface.TaggedVlans = nil
+	case strings.HasPrefix(iface.Name, iosVlanInterfacePrefix):
+		iface.InterfaceType = "vlan"
+		iface.VlanId = strings.TrimPrefix(iface.Name, iosVlanInterfacePrefix)
+	case strings.HasPrefix(iface.Name, iosPortChannelName):
+		iface.InterfaceType = "aggregate"
+	case strings.HasPrefix(iface.Name, "Loopback"), strings.HasPrefix(iface.Name, "Tunnel"):
+		iface.InterfaceType = "virtual"
+	default:
+		iface.InterfaceType = "physical"
+	}
+
+	return iface, channelGroup
+}
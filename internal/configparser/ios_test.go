@@ -0,0 +1,123 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+func findInterface(t *testing.T, interfaces *[]model.FortigateInterface, name string) model.FortigateInterface {
+	t.Helper()
+	index := slices.IndexFunc(*interfaces, func(iface model.FortigateInterface) bool { return iface.Name == name })
+	if index == -1 {
+		t.Fatalf("interface %q not found", name)
+	}
+	return (*interfaces)[index]
+}
+
+const iosFixture = `!
+interface Port-channel1
+ description uplink
+ switchport mode trunk
+ switchport trunk native vlan 99
+ switchport trunk allowed vlan 10,20-22
+ switchport trunk allowed vlan add 30
+ switchport trunk allowed vlan remove 21
+!
+interface GigabitEthernet1/0/1
+ description member 1
+ switchport mode trunk
+ channel-group 1 mode active
+!
+interface GigabitEthernet1/0/2
+ channel-group 1 mode active
+ shutdown
+!
+interface GigabitEthernet1/0/3
+ switchport mode access
+ switchport access vlan 10
+!
+interface GigabitEthernet1/0/4
+ switchport mode trunk
+!
+interface GigabitEthernet0/0
+ no switchport
+ vrf forwarding MGMT
+!
+interface GigabitEthernet0/0.100
+ encapsulation dot1Q 100
+!
+interface Port-channel2.200
+ encapsulation dot1Q 200 native
+!
+interface Vlan10
+ description servers
+ ip address 10.0.10.1 255.255.255.0
+ ip address 10.0.11.1 255.255.255.0 secondary
+ ipv6 address 2001:db8:10::1/64
+ ipv6 address FE80::1 link-local
+!
+interface Loopback0
+ ip vrf forwarding CUST
+!
+interface Null0
+ no ip unreachables
+!
+`
+
+func TestParseIOSConfig(t *testing.T) {
+	config := iosFixture
+	interfaces, err := ParseIOSConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		interfaceType string
+		status        string
+		parent        string
+		vlanMode      string
+		vlanId        string
+		taggedVlans   []string
+		members       []string
+	}{
+		{"Port-channel1", "aggregate", "", "", vlanModeTagged, "99", []string{"10", "20", "22", "30"}, []string{"GigabitEthernet1/0/1", "GigabitEthernet1/0/2"}},
+		{"GigabitEthernet1/0/1", "physical", "", "", vlanModeTaggedAll, "", nil, nil},
+		{"GigabitEthernet1/0/2", "physical", "down", "", "", "", nil, nil},
+		{"GigabitEthernet1/0/3", "physical", "", "", vlanModeAccess, "10", nil, nil},
+		{"GigabitEthernet1/0/4", "physical", "", "", vlanModeTaggedAll, "", nil, nil},
+		{"GigabitEthernet0/0", "physical", "", "", "", "", nil, nil},
+		{"GigabitEthernet0/0.100", "vlan", "", "GigabitEthernet0/0", "", "100", nil, nil},
+		{"Port-channel2.200", "vlan", "", "Port-channel2", "", "200", nil, nil},
+		{"Vlan10", "vlan", "", "", "", "10", nil, nil},
+		{"Loopback0", "virtual", "", "", "", "", nil, nil},
+	}
+	for _, tt := range tests {
+		iface := findInterface(t, interfaces, tt.name)
+		if iface.InterfaceType != tt.interfaceType || iface.Status != tt.status || iface.Parent != tt.parent {
+			t.Errorf("%s: got type %q status %q parent %q, want %q %q %q", tt.name, iface.InterfaceType, iface.Status, iface.Parent, tt.interfaceType, tt.status, tt.parent)
+		}
+		if iface.VlanMode != tt.vlanMode || iface.VlanId != tt.vlanId || !slices.Equal(iface.TaggedVlans, tt.taggedVlans) {
+			t.Errorf("%s: got mode %q vlan %q tagged %v, want %q %q %v", tt.name, iface.VlanMode, iface.VlanId, iface.TaggedVlans, tt.vlanMode, tt.vlanId, tt.taggedVlans)
+		}
+		if !slices.Equal(iface.Members, tt.members) {
+			t.Errorf("%s: got members %v, want %v", tt.name, iface.Members, tt.members)
+		}
+	}
+
+	if iface := findInterface(t, interfaces, "Vlan10"); !slices.Equal(iface.IPAddresses, []string{"10.0.10.1/24", "10.0.11.1/24", "2001:db8:10::1/64"}) {
+		t.Errorf("Vlan10: got addresses %v", iface.IPAddresses)
+	}
+
+	for name, vrf := range map[string]string{"GigabitEthernet0/0": "MGMT", "Loopback0": "CUST", "Vlan10": ""} {
+		if iface := findInterface(t, interfaces, name); iface.Vrf != vrf {
+			t.Errorf("%s: got vrf %q, want %q", name, iface.Vrf, vrf)
+		}
+	}
+
+	if len(*interfaces) != len(tests) {
+		t.Errorf("got %d interfaces, want %d", len(*interfaces), len(tests))
+	}
+}
@@ -0,0 +1,293 @@
+package configparser
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+var (
+	junosLagName     = regexp.MustCompile(`^(ae|reth)\d+$`)
+	junosVirtualName = regexp.MustCompile(`^(lo\d+|irb|vlan|st\d+|gr-.*|ip-.*|lt-.*|vme)$`)
+)
+
+type junosUnit struct {
+	name        string
+	description string
+	disabled    bool
+	vlanId      string
+	switchMode  string
+	vlanMembers []string
+	addresses   []string
+}
+
+type junosInterface struct {
+	name        string
+	description string
+	disabled    bool
+	lag         string
+	nativeVlan  string
+	units       []*junosUnit
+}
+
+func ParseJunosConfig(config *string) (*[]model.FortigateInterface, error) {
+	statements := parseHierarchicalConfig(config)
+
+	vlanIds := map[string]string{}
+	l3Interfaces := map[string]string{}
+	for _, statement := range statementsWithPrefix(statements, "vlans") {
+		if len(statement) < 3 {
+			continue
+		}
+		switch statement[1] {
+		case "vlan-id":
+			vlanIds[statement[0]] = statement[2]
+		case "l3-interface":
+			l3Interfaces[statement[2]] = statement[0]
+		}
+	}
+
+	junosInterfaces := parseJunosInterfaces(statementsWithPrefix(statements, "interfaces"))
+
+	var deviceInterfaces []model.FortigateInterface
+	lagMembers := map[string][]string{}
+
+	for _, junosIface := range junosInterfaces {
+		var iface model.FortigateInterface
+		iface.Name = junosIface.name
+		iface.Description = junosIface.description
+		if junosIface.disabled {
+			iface.Status = "down"
+		}
+
+		switch {
+		case junosLagName.MatchString(junosIface.name):
+			iface.InterfaceType = "aggregate"
+		case junosVirtualName.MatchString(junosIface.name):
+			iface.InterfaceType = "virtual"
+		default:
+			iface.InterfaceType = "physical"
+		}
+
+		if junosIface.lag != "" {
+			lagMembers[junosIface.lag] = append(lagMembers[junosIface.lag], junosIface.name)
+		}
+
+		var units []model.FortigateInterface
+		for _, unit := range junosIface.units {
+			if unit.switchMode != "" {
+				applyJunosSwitching(&iface, unit, junosIface.nativeVlan, vlanIds)
+				continue
+			}
+
+			var child model.FortigateInterface
+			child.Name = junosIface.name + "." + unit.name
+			child.Parent = junosIface.name
+			child.Description = unit.description
+			child.IPAddresses = unit.addresses
+			if unit.disabled {
+				child.Status = "down"
+			}
+
+			vlanId := unit.vlanId
+			if vlanName, ok := l3Interfaces[child.Name]; ok && vlanId == "" {
+				vlanId = vlanIds[vlanName]
+			}
+			if vlanId != "" {
+				child.InterfaceType = "vlan"
+				child.VlanId = vlanId
+			} else {
+				child.InterfaceType = "virtual"
+			}
+			units = append(units, child)
+		}
+
+		deviceInterfaces = append(deviceInterfaces, iface)
+		deviceInterfaces = append(deviceInterfaces, units...)
+	}
+
+	addLagMembers(&deviceInterfaces, lagMembers)
+
+	// SRX: security zones security-zone trust interfaces ge-0/0/1.0
+	for _, statement := range statementsWithPrefix(statements, "security", "zones") {
+		if len(statement) < 4 || statement[2] != "interfaces" {
+			continue
+		}
+		if index := findJunosInterface(&deviceInterfaces, statement[3]); index != -1 {
+			deviceInterfaces[index].Zone = statement[1]
+		}
+	}
+
+	// routing-instances RED interface ge-0/0/1.0, layer 2 instances are not a vrf
+	routingInstances := statementsWithPrefix(statements, "routing-instances")
+	instanceTypes := map[string]string{}
+	for _, statement := range routingInstances {
+		if len(statement) >= 3 && statement[1] == "instance-type" {
+			instanceTypes[statement[0]] = statement[2]
+		}
+	}
+	for _, statement := range routingInstances {
+		if len(statement) < 3 || statement[1] != "interface" {
+			continue
+		}
+		switch instanceTypes[statement[0]] {
+		case "virtual-switch", "mac-vrf", "evpn", "l2vpn", "vpls", "forwarding":
+			continue
+		}
+		if index := findJunosInterface(&deviceInterfaces, statement[2]); index != -1 {
+			deviceInterfaces[index].Vrf = statement[0]
+		}
+	}
+
+	return &deviceInterfaces, nil
+}
+
+// findJunosInterface returns the index of the unit, or of the interface itself when
+// the unit is applied to its parent like an ethernet-switching unit 0.
+func findJunosInterface(deviceInterfaces *[]model.FortigateInterface, name string) int {
+	for index, iface := range *deviceInterfaces {
+		if iface.Name == name {
+			return index
+		}
+	}
+	baseName, _, _ := strings.Cut(name, ".")
+	for index, iface := range *deviceInterfaces {
+		if iface.Name == baseName {
+			return index
+		}
+	}
+	return -1
+}
+
+func parseJunosInterfaces(statements [][]string) []*junosInterface {
+	var result []*junosInterface
+	byName := map[string]*junosInterface{}
+
+	for _, statement := range statements {
+		if len(statement) == 0 || statement[0] == "interface-range" || strings.HasPrefix(statement[0], "<") {
+			continue
+		}
+
+		iface, ok := byName[statement[0]]
+		if !ok {
+			iface = &junosInterface{name: statement[0]}
+			byName[statement[0]] = iface
+			result = append(result, iface)
+		}
+
+		attributes := statement[1:]
+		if len(attributes) == 0 {
+			continue
+		}
+
+		switch attributes[0] {
+		case "description":
+			iface.description = strings.Join(attributes[1:], " ")
+		case "disable":
+			iface.disabled = true
+		case "native-vlan-id":
+			if len(attributes) > 1 {
+				iface.nativeVlan = attributes[1]
+			}
+		case "ether-options", "gigether-options":
+			// 802.3ad ae0, or redundant-parent reth0 on an SRX chassis cluster
+			if len(attributes) > 2 && (attributes[1] == "802.3ad" || attributes[1] == "redundant-parent") {
+				iface.lag = attributes[2]
+			}
+		case "unit":
+			if len(attributes) > 1 {
+				parseJunosUnit(iface, attributes[1], attributes[2:])
+			}
+		}
+	}
+
+	return result
+}
+
+func parseJunosUnit(iface *junosInterface, unitName string, attributes []string) {
+	idx := slices.IndexFunc(iface.units, func(u *junosUnit) bool { return u.name == unitName })
+	if idx == -1 {
+		iface.units = append(iface.units, &junosUnit{name: unitName})
+		idx = len(iface.units) - 1
+	}
+	unit := iface.units[idx]
+
+	if len(attributes) == 0 {
+		return
+	}
+
+	switch attributes[0] {
+	case "description":
+		unit.description = strings.Join(attributes[1:], " ")
+	case "disable":
+		unit.disabled = true
+	case "vlan-id":
+		if len(attributes) > 1 {
+			unit.vlanId = attributes[1]
+		}
+	case "family":
+		// family inet address 192.0.2.1/24, vrrp-group statements repeat the address
+		if len(attributes) > 3 && (attributes[1] == "inet" || attributes[1] == "inet6") && attributes[2] == "address" {
+			if strings.Contains(attributes[3], "/") && !slices.Contains(unit.addresses, attributes[3]) {
+				unit.addresses = append(unit.addresses, attributes[3])
+			}
+			return
+		}
+		if len(attributes) < 2 || attributes[1] != "ethernet-switching" {
+			return
+		}
+		if unit.switchMode == "" {
+			unit.switchMode = vlanModeAccess
+		}
+		switching := attributes[2:]
+		if len(switching) < 2 {
+			return
+		}
+		switch {
+		case switching[0] == "port-mode" || switching[0] == "interface-mode":
+			if switching[1] == "trunk" {
+				unit.switchMode = vlanModeTagged
+			}
+		case switching[0] == "vlan" && switching[1] == "members" && len(switching) > 2:
+			unit.vlanMembers = append(unit.vlanMembers, switching[2])
+		}
+	}
+}
+
+// applyJunosSwitching moves the ethernet-switching settings of a unit to its
+// physical interface, resolving vlan names to their ids.
+func applyJunosSwitching(iface *model.FortigateInterface, unit *junosUnit, nativeVlan string, vlanIds map[string]string) {
+	var vlans []string
+	allVlans := false
+	for _, member := range unit.vlanMembers {
+		if member == "all" {
+			allVlans = true
+			continue
+		}
+		if vlanId, ok := vlanIds[member]; ok {
+			vlans = append(vlans, vlanId)
+			continue
+		}
+		vlans = append(vlans, expandVlanList(member)...)
+	}
+
+	if unit.description != "" && iface.Description == "" {
+		iface.Description = unit.description
+	}
+
+	if unit.switchMode == vlanModeAccess {
+		iface.VlanMode = vlanModeAccess
+		if len(vlans) > 0 {
+			iface.VlanId = vlans[0]
+		}
+		return
+	}
+
+	iface.VlanMode = trunkVlanMode(allVlans)
+	iface.VlanId = nativeVlan
+	if !allVlans {
+		iface.TaggedVlans = vlans
+	}
+}
@@ -0,0 +1,260 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+)
+
+const junosBraceFixture = `## Last commit: 2024-01-01 10:00:00 UTC by admin
+version 21.4R3;
+interfaces {
+    ge-0/0/0 {
+        description "uplink member";
+        ether-options {
+            802.3ad ae0;
+        }
+    }
+    ge-0/0/1 {
+        gigether-options {
+            802.3ad ae0;
+        }
+    }
+    ge-0/0/2 {
+        disable;
+        unit 0 {
+            family ethernet-switching {
+                vlan {
+                    members servers;
+                }
+            }
+        }
+    }
+    ge-0/0/3 {
+        vlan-tagging;
+        unit 100 {
+            description "customer a";
+            vlan-id 100;
+            family inet {
+                address 192.0.2.2/24 {
+                    vrrp-group 1 {
+                        virtual-address 192.0.2.1;
+                    }
+                }
+            }
+            family inet6 {
+                address 2001:db8::2/64;
+            }
+        }
+        unit 200 {
+            disable;
+            vlan-id 200;
+        }
+    }
+    ae0 {
+        native-vlan-id 99;
+        unit 0 {
+            family ethernet-switching {
+                interface-mode trunk;
+                vlan {
+                    members [ servers 20-21 ];
+                }
+            }
+        }
+    }
+    irb {
+        unit 10;
+    }
+    lo0 {
+        unit 0 {
+            family inet {
+                address 10.255.0.1/32;
+            }
+        }
+    }
+}
+vlans {
+    servers {
+        vlan-id 10;
+        l3-interface irb.10;
+    }
+}
+`
+
+const junosSetFixture = `set version 21.4R3
+set interfaces ge-0/0/0 description "uplink member"
+set interfaces ge-0/0/0 ether-options 802.3ad ae0
+set interfaces ge-0/0/1 gigether-options 802.3ad ae0
+set interfaces ge-0/0/2 disable
+set interfaces ge-0/0/2 unit 0 family ethernet-switching vlan members servers
+set interfaces ge-0/0/3 vlan-tagging
+set interfaces ge-0/0/3 unit 100 description "customer a"
+set interfaces ge-0/0/3 unit 100 vlan-id 100
+set interfaces ge-0/0/3 unit 100 family inet address 192.0.2.2/24 vrrp-group 1 virtual-address 192.0.2.1
+set interfaces ge-0/0/3 unit 100 family inet6 address 2001:db8::2/64
+set interfaces ge-0/0/3 unit 200 disable
+set interfaces ge-0/0/3 unit 200 vlan-id 200
+set interfaces ae0 native-vlan-id 99
+set interfaces ae0 unit 0 family ethernet-switching interface-mode trunk
+set interfaces ae0 unit 0 family ethernet-switching vlan members servers
+set interfaces ae0 unit 0 family ethernet-switching vlan members 20-21
+set interfaces irb unit 10
+set interfaces lo0 unit 0 family inet address 10.255.0.1/32
+set vlans servers vlan-id 10
+set vlans servers l3-interface irb.10
+`
+
+func TestParseJunosConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		interfaceType string
+		status        string
+		description   string
+		parent        string
+		vlanMode      string
+		vlanId        string
+		taggedVlans   []string
+		members       []string
+	}{
+		{"ge-0/0/0", "physical", "", "uplink member", "", "", "", nil, nil},
+		{"ge-0/0/1", "physical", "", "", "", "", "", nil, nil},
+		{"ge-0/0/2", "physical", "down", "", "", vlanModeAccess, "10", nil, nil},
+		{"ge-0/0/3", "physical", "", "", "", "", "", nil, nil},
+		{"ge-0/0/3.100", "vlan", "", "customer a", "ge-0/0/3", "", "100", nil, nil},
+		{"ge-0/0/3.200", "vlan", "down", "", "ge-0/0/3", "", "200", nil, nil},
+		{"ae0", "aggregate", "", "", "", vlanModeTagged, "99", []string{"10", "20", "21"}, []string{"ge-0/0/0", "ge-0/0/1"}},
+		{"irb", "virtual", "", "", "", "", "", nil, nil},
+		{"irb.10", "vlan", "", "", "irb", "", "10", nil, nil},
+		{"lo0", "virtual", "", "", "", "", "", nil, nil},
+		{"lo0.0", "virtual", "", "", "lo0", "", "", nil, nil},
+	}
+
+	for format, fixture := range map[string]string{"brace": junosBraceFixture, "set": junosSetFixture} {
+		config := fixture
+		interfaces, err := ParseJunosConfig(&config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, tt := range tests {
+			iface := findInterface(t, interfaces, tt.name)
+			if iface.InterfaceType != tt.interfaceType || iface.Status != tt.status || iface.Description != tt.description || iface.Parent != tt.parent {
+				t.Errorf("%s %s: got type %q status %q description %q parent %q, want %q %q %q %q", format, tt.name,
+					iface.InterfaceType, iface.Status, iface.Description, iface.Parent, tt.interfaceType, tt.status, tt.description, tt.parent)
+			}
+			if iface.VlanMode != tt.vlanMode || iface.VlanId != tt.vlanId || !slices.Equal(iface.TaggedVlans, tt.taggedVlans) {
+				t.Errorf("%s %s: got mode %q vlan %q tagged %v, want %q %q %v", format, tt.name,
+					iface.VlanMode, iface.VlanId, iface.TaggedVlans, tt.vlanMode, tt.vlanId, tt.taggedVlans)
+			}
+			if !slices.Equal(iface.Members, tt.members) {
+				t.Errorf("%s %s: got members %v, want %v", format, tt.name, iface.Members, tt.members)
+			}
+		}
+
+		addresses := map[string][]string{
+			"ge-0/0/3.100": {"192.0.2.2/24", "2001:db8::2/64"},
+			"lo0.0":        {"10.255.0.1/32"},
+			"ge-0/0/3":     nil,
+		}
+		for name, want := range addresses {
+			if iface := findInterface(t, interfaces, name); !slices.Equal(iface.IPAddresses, want) {
+				t.Errorf("%s %s: got addresses %v, want %v", format, name, iface.IPAddresses, want)
+			}
+		}
+
+		if len(*interfaces) != len(tests) {
+			t.Errorf("%s: got %d interfaces, want %d", format, len(*interfaces), len(tests))
+		}
+	}
+}
+
+func TestParseJunosConfigSecurityZones(t *testing.T) {
+	config := `interfaces {
+    ge-0/0/0 {
+        unit 0 {
+            family inet;
+        }
+    }
+    ge-0/0/1 {
+        unit 0 {
+            family ethernet-switching;
+        }
+    }
+}
+security {
+    zones {
+        security-zone trust {
+            interfaces {
+                ge-0/0/0.0;
+                ge-0/0/1.0 {
+                    host-inbound-traffic {
+                        system-services {
+                            ping;
+                        }
+                    }
+                }
+            }
+        }
+        security-zone untrust {
+            interfaces {
+                ge-0/0/9.0;
+            }
+        }
+    }
+}
+`
+	interfaces, err := ParseJunosConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, zone := range map[string]string{"ge-0/0/0": "", "ge-0/0/0.0": "trust", "ge-0/0/1": "trust"} {
+		if iface := findInterface(t, interfaces, name); iface.Zone != zone {
+			t.Errorf("%s: got zone %q, want %q", name, iface.Zone, zone)
+		}
+	}
+}
+
+func TestParseJunosConfigRoutingInstances(t *testing.T) {
+	config := `set interfaces ge-0/0/0 unit 0 family inet address 192.0.2.1/24
+set interfaces ge-0/0/1 unit 0 family inet address 198.51.100.1/24
+set interfaces ge-0/0/2 unit 0 family ethernet-switching
+set interfaces lo0 unit 1 family inet address 10.255.1.1/32
+set routing-instances RED instance-type virtual-router
+set routing-instances RED interface ge-0/0/0.0
+set routing-instances RED interface lo0.1
+set routing-instances BLUE instance-type vrf
+set routing-instances BLUE interface ge-0/0/1.0
+set routing-instances L2 instance-type virtual-switch
+set routing-instances L2 interface ge-0/0/2.0
+`
+	interfaces, err := ParseJunosConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, vrf := range map[string]string{"ge-0/0/0.0": "RED", "lo0.1": "RED", "ge-0/0/1.0": "BLUE", "ge-0/0/2": "", "ge-0/0/0": ""} {
+		if iface := findInterface(t, interfaces, name); iface.Vrf != vrf {
+			t.Errorf("%s: got vrf %q, want %q", name, iface.Vrf, vrf)
+		}
+	}
+}
+
+func TestParseJunosConfigRedundantEthernet(t *testing.T) {
+	config := `set interfaces ge-0/0/4 gigether-options redundant-parent reth0
+set interfaces ge-7/0/4 gigether-options redundant-parent reth0
+set interfaces reth0 redundant-ether-options redundancy-group 1
+set interfaces reth0 unit 0 family inet address 192.0.2.1/24
+`
+	interfaces, err := ParseJunosConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reth := findInterface(t, interfaces, "reth0")
+	if reth.InterfaceType != "aggregate" || !slices.Equal(reth.Members, []string{"ge-0/0/4", "ge-7/0/4"}) {
+		t.Errorf("reth0: got type %q members %v, want aggregate [ge-0/0/4 ge-7/0/4]", reth.InterfaceType, reth.Members)
+	}
+	if unit := findInterface(t, interfaces, "reth0.0"); unit.Parent != "reth0" || !slices.Equal(unit.IPAddresses, []string{"192.0.2.1/24"}) {
+		t.Errorf("reth0.0: got parent %q addresses %v", unit.Parent, unit.IPAddresses)
+	}
+}
@@ -0,0 +1,131 @@
+package configparser
+
+import (
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	nxosEncapsulation   = "encapsulation dot1q "
+	nxosVpc             = "vpc "
+	nxosVrfMember       = "vrf member "
+	nxosIPAddress       = "ip address "
+	nxosIPv6Address     = "ipv6 address "
+	nxosPortChannelName = "port-channel"
+)
+
+func ParseNXOSConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+	channelGroups := map[string][]string{}
+
+	for _, section := range parseIndentedSections(config, iosInterfacePrefix) {
+		iface, channelGroup := parseNXOSInterface(section)
+		if iface.Name == "" {
+			continue
+		}
+		if channelGroup != "" {
+			lagName := nxosPortChannelName + channelGroup
+			channelGroups[lagName] = append(channelGroups[lagName], iface.Name)
+		}
+		deviceInterfaces = append(deviceInterfaces, iface)
+	}
+
+	addLagMembers(&deviceInterfaces, channelGroups)
+
+	return &deviceInterfaces, nil
+}
+
+func parseNXOSInterface(section configSection) (model.FortigateInterface, string) {
+	var iface model.FortigateInterface
+	var channelGroup, accessVlan, nativeVlan, vpc, description string
+	var allowedVlans []string
+	allowAll := true
+	switchport := false
+
+	fields := strings.Fields(section.header)
+	if len(fields) == 0 {
+		return iface, ""
+	}
+	iface.Name = fields[0]
+
+	for _, line := range section.lines {
+		switch {
+		case strings.HasPrefix(line, iosDescription):
+			description = strings.TrimPrefix(line, iosDescription)
+		case line == iosShutdown:
+			iface.Status = "down"
+		case line == "switchport":
+			switchport = true
+		case strings.HasPrefix(line, iosChannelGroup):
+			channelGroup = strings.Fields(strings.TrimPrefix(line, iosChannelGroup))[0]
+		case strings.HasPrefix(line, nxosEncapsulation):
+			iface.VlanId = strings.TrimPrefix(line, nxosEncapsulation)
+		case strings.HasPrefix(line, nxosVpc):
+			vpc = strings.TrimPrefix(line, nxosVpc)
+		case line == "vpc peer-link":
+			vpc = "peer-link"
+		case strings.HasPrefix(line, nxosVrfMember):
+			iface.Vrf = strings.TrimPrefix(line, nxosVrfMember)
+		case strings.HasPrefix(line, nxosIPAddress), strings.HasPrefix(line, nxosIPv6Address):
+			addressFields := strings.Fields(line)
+			if len(addressFields) > 2 && strings.Contains(addressFields[2], "/") {
+				iface.IPAddresses = append(iface.IPAddresses, addressFields[2])
+			}
+		case strings.HasPrefix(line, iosSwitchportMode):
+			switch strings.TrimPrefix(line, iosSwitchportMode) {
+			case "access":
+				iface.VlanMode = vlanModeAccess
+			case "trunk":
+				iface.VlanMode = vlanModeTagged
+			}
+		case strings.HasPrefix(line, iosAccessVlan):
+			accessVlan = strings.TrimPrefix(line, iosAccessVlan)
+		case strings.HasPrefix(line, iosNativeVlan):
+			nativeVlan = strings.TrimPrefix(line, iosNativeVlan)
+		case strings.HasPrefix(line, iosTrunkAllowedVlan):
+			allowedVlans, allowAll = applyVlanListCommand(allowedVlans, strings.TrimPrefix(line, iosTrunkAllowedVlan))
+		}
+	}
+
+	// NX-OS switchports are access ports unless configured otherwise
+	if iface.VlanMode == "" && (switchport || accessVlan != "") {
+		iface.VlanMode = vlanModeAccess
+	}
+
+	switch iface.VlanMode {
+	case vlanModeAccess:
+		iface.VlanId = accessVlan
+	case vlanModeTagged:
+		iface.VlanId = nativeVlan
+		iface.VlanMode = trunkVlanMode(allowAll)
+		iface.TaggedVlans = allowedVlans
+	}
+
+	iface.Description = description
+	if vpc != "" {
+		createDescriptionBuilder(vpc, "vpc", &iface.Description)
+	}
+
+	baseName, _, isSubinterface := strings.Cut(iface.Name, ".")
+	lowerName := strings.ToLower(iface.Name)
+
+	switch {
+	case isSubinterface:
+		iface.InterfaceType = "vlan"
+		iface.Parent = baseName
+		iface.VlanMode = ""
+		iface.TaggedVlans = nil
+	case strings.HasPrefix(lowerName, "vlan"):
+		iface.InterfaceType = "vlan"
+		iface.VlanId = iface.Name[len("vlan"):]
+	case strings.HasPrefix(lowerName, nxosPortChannelName):
+		iface.InterfaceType = "aggregate"
+	case strings.HasPrefix(lowerName, "loopback"), strings.HasPrefix(lowerName, "tunnel"), strings.HasPrefix(lowerName, "nve"):
+		iface.InterfaceType = "virtual"
+	default:
+		iface.InterfaceType = "physical"
+	}
+
+	return iface, channelGroup
+}
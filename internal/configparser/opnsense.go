@@ -0,0 +1,204 @@
+package configparser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+type opnsenseInterface struct {
+	XMLName  xml.Name
+	If       string  `xml:"if"`
+	Descr    string  `xml:"descr"`
+	Enable   *string `xml:"enable"`
+	IPAddr   string  `xml:"ipaddr"`
+	Subnet   string  `xml:"subnet"`
+	IPAddrV6 string  `xml:"ipaddrv6"`
+	SubnetV6 string  `xml:"subnetv6"`
+}
+
+type opnsenseVlan struct {
+	If     string `xml:"if"`
+	Tag    string `xml:"tag"`
+	Descr  string `xml:"descr"`
+	Vlanif string `xml:"vlanif"`
+}
+
+type opnsenseLagg struct {
+	Members string `xml:"members"`
+	Laggif  string `xml:"laggif"`
+	Proto   string `xml:"proto"`
+	Descr   string `xml:"descr"`
+}
+
+type opnsenseBridge struct {
+	Members  string `xml:"members"`
+	Bridgeif string `xml:"bridgeif"`
+	Descr    string `xml:"descr"`
+}
+
+// opnsenseConfig covers the config.xml of both OPNsense and pfSense, only the root element differs.
+type opnsenseConfig struct {
+	Interfaces struct {
+		Items []opnsenseInterface `xml:",any"`
+	} `xml:"interfaces"`
+	Vlans   []opnsenseVlan   `xml:"vlans>vlan"`
+	Laggs   []opnsenseLagg   `xml:"laggs>lagg"`
+	Bridges []opnsenseBridge `xml:"bridges>bridged"`
+}
+
+var opnsenseVirtualPrefixes = []string{"lo", "ovpn", "wg", "gif", "gre", "tun", "enc", "ipsec", "pppoe"}
+
+func ParseOPNsenseConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+
+	start := strings.Index(*config, "<")
+	if start == -1 {
+		return &deviceInterfaces, fmt.Errorf("no config.xml found")
+	}
+
+	var opnsense opnsenseConfig
+	if err := xml.Unmarshal([]byte((*config)[start:]), &opnsense); err != nil {
+		return &deviceInterfaces, fmt.Errorf("could not parse config.xml: %s", err)
+	}
+
+	for _, lagg := range opnsense.Laggs {
+		var aggr model.FortigateInterface
+		aggr.InterfaceType = "aggregate"
+		aggr.Name = lagg.Laggif
+		aggr.Description = lagg.Descr
+		if lagg.Proto != "" {
+			createDescriptionBuilder(lagg.Proto, "proto", &aggr.Description)
+		}
+		for _, member := range strings.Split(lagg.Members, ",") {
+			if member != "" {
+				aggr.Members = append(aggr.Members, member)
+			}
+		}
+		deviceInterfaces = append(deviceInterfaces, aggr)
+	}
+
+	for _, vlan := range opnsense.Vlans {
+		var vid model.FortigateInterface
+		vid.InterfaceType = "vlan"
+		vid.Name = vlan.Vlanif
+		if vid.Name == "" {
+			vid.Name = vlan.If + "." + vlan.Tag
+		}
+		vid.Description = vlan.Descr
+		vid.VlanId = vlan.Tag
+		vid.Parent = vlan.If
+		deviceInterfaces = append(deviceInterfaces, vid)
+	}
+
+	// interfaces are assigned with a logical name (wan, lan, opt1) to a device (igb0)
+	assignments := map[string]string{}
+	for _, assignment := range opnsense.Interfaces.Items {
+		assignments[assignment.XMLName.Local] = assignment.If
+	}
+
+	var bridges []model.FortigateVirtualSwitch
+	for _, bridge := range opnsense.Bridges {
+		var vSwitch model.FortigateVirtualSwitch
+		vSwitch.Name = bridge.Bridgeif
+		for _, member := range strings.Split(bridge.Members, ",") {
+			if device, ok := assignments[member]; ok {
+				vSwitch.Members = append(vSwitch.Members, device)
+			} else if member != "" {
+				vSwitch.Members = append(vSwitch.Members, member)
+			}
+		}
+		bridges = append(bridges, vSwitch)
+	}
+
+	for _, assignment := range opnsense.Interfaces.Items {
+		if assignment.If == "" || strings.HasPrefix(assignment.If, "bridge") {
+			continue
+		}
+
+		index := -1
+		for i, iface := range deviceInterfaces {
+			if iface.Name == assignment.If {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			var iface model.FortigateInterface
+			iface.Name = assignment.If
+			iface.InterfaceType = "physical"
+			if hasAnyPrefix(assignment.If, opnsenseVirtualPrefixes) {
+				iface.InterfaceType = "virtual"
+			}
+			deviceInterfaces = append(deviceInterfaces, iface)
+			index = len(deviceInterfaces) - 1
+		}
+
+		applyOPNsenseAssignment(&deviceInterfaces[index], assignment)
+	}
+
+	addOPNsenseMembers(&deviceInterfaces, &bridges)
+	convertVirtualSwitch(&bridges, &deviceInterfaces)
+
+	for _, assignment := range opnsense.Interfaces.Items {
+		if !strings.HasPrefix(assignment.If, "bridge") {
+			continue
+		}
+		for index, iface := range deviceInterfaces {
+			if iface.Name == assignment.If {
+				applyOPNsenseAssignment(&deviceInterfaces[index], assignment)
+			}
+		}
+	}
+
+	return &deviceInterfaces, nil
+}
+
+func applyOPNsenseAssignment(iface *model.FortigateInterface, assignment opnsenseInterface) {
+	iface.Label = assignment.Descr
+	if iface.Label == "" {
+		iface.Label = strings.ToUpper(assignment.XMLName.Local)
+	}
+	if assignment.Enable == nil {
+		iface.Status = "down"
+	}
+	if net.ParseIP(assignment.IPAddr) != nil && assignment.Subnet != "" {
+		iface.IPAddresses = append(iface.IPAddresses, assignment.IPAddr+"/"+assignment.Subnet)
+	}
+	if net.ParseIP(assignment.IPAddrV6) != nil && assignment.SubnetV6 != "" {
+		iface.IPAddresses = append(iface.IPAddresses, assignment.IPAddrV6+"/"+assignment.SubnetV6)
+	}
+}
+
+// addOPNsenseMembers adds the lagg and bridge members that are not assigned as interface themselves.
+func addOPNsenseMembers(deviceInterfaces *[]model.FortigateInterface, bridges *[]model.FortigateVirtualSwitch) {
+	known := map[string]bool{}
+	for _, iface := range *deviceInterfaces {
+		known[iface.Name] = true
+	}
+
+	var members []string
+	for _, iface := range *deviceInterfaces {
+		members = append(members, iface.Members...)
+		if iface.Parent != "" {
+			members = append(members, iface.Parent)
+		}
+	}
+	for _, bridge := range *bridges {
+		members = append(members, bridge.Members...)
+	}
+
+	for _, member := range members {
+		if known[member] || hasAnyPrefix(member, opnsenseVirtualPrefixes) {
+			continue
+		}
+		var pyh model.FortigateInterface
+		pyh.Name = member
+		pyh.InterfaceType = "physical"
+		*deviceInterfaces = append(*deviceInterfaces, pyh)
+		known[member] = true
+	}
+}
@@ -0,0 +1,134 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+)
+
+const opnsenseFixture = `<?xml version="1.0"?>
+<opnsense>
+  <version>24.1</version>
+  <interfaces>
+    <wan>
+      <if>igb0</if>
+      <descr>Internet</descr>
+      <enable>1</enable>
+      <ipaddr>192.0.2.2</ipaddr>
+      <subnet>24</subnet>
+      <ipaddrv6>dhcp6</ipaddrv6>
+    </wan>
+    <lan>
+      <if>lagg0</if>
+      <enable>1</enable>
+      <ipaddr>10.0.0.1</ipaddr>
+      <subnet>24</subnet>
+      <ipaddrv6>2001:db8::1</ipaddrv6>
+      <subnetv6>64</subnetv6>
+    </lan>
+    <opt1>
+      <if>vlan0.100</if>
+      <descr>SERVERS</descr>
+      <enable>1</enable>
+      <ipaddr>10.0.100.1</ipaddr>
+      <subnet>24</subnet>
+    </opt1>
+    <opt2>
+      <if>igb3</if>
+      <descr>spare</descr>
+    </opt2>
+    <opt3>
+      <if>bridge0</if>
+      <descr>BRIDGE</descr>
+      <enable>1</enable>
+      <ipaddr>10.0.50.1</ipaddr>
+      <subnet>24</subnet>
+    </opt3>
+    <opt4>
+      <if>igb4</if>
+      <enable>1</enable>
+    </opt4>
+    <lo0>
+      <if>lo0</if>
+      <descr>Loopback</descr>
+      <enable>1</enable>
+      <ipaddr>127.0.0.1</ipaddr>
+      <subnet>8</subnet>
+    </lo0>
+  </interfaces>
+  <vlans>
+    <vlan>
+      <if>igb1</if>
+      <tag>100</tag>
+      <descr>servers</descr>
+      <vlanif>vlan0.100</vlanif>
+    </vlan>
+    <vlan>
+      <if>igb1</if>
+      <tag>200</tag>
+    </vlan>
+  </vlans>
+  <laggs>
+    <lagg>
+      <members>igb5,igb6</members>
+      <laggif>lagg0</laggif>
+      <proto>lacp</proto>
+      <descr>core</descr>
+    </lagg>
+  </laggs>
+  <bridges>
+    <bridged>
+      <members>opt4,igb7</members>
+      <bridgeif>bridge0</bridgeif>
+    </bridged>
+  </bridges>
+</opnsense>
+`
+
+func TestParseOPNsenseConfig(t *testing.T) {
+	config := opnsenseFixture
+	interfaces, err := ParseOPNsenseConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		interfaceType string
+		status        string
+		description   string
+		label         string
+		parent        string
+		vlanId        string
+		members       []string
+		ipAddresses   []string
+	}{
+		{"igb0", "physical", "", "", "Internet", "", "", nil, []string{"192.0.2.2/24"}},
+		{"lagg0", "aggregate", "", "core; proto: lacp", "LAN", "", "", []string{"igb5", "igb6"}, []string{"10.0.0.1/24", "2001:db8::1/64"}},
+		{"igb5", "physical", "", "", "", "", "", nil, nil},
+		{"vlan0.100", "vlan", "", "servers", "SERVERS", "igb1", "100", nil, []string{"10.0.100.1/24"}},
+		{"igb1.200", "vlan", "", "", "", "igb1", "200", nil, nil},
+		{"igb1", "physical", "", "", "", "", "", nil, nil},
+		{"igb3", "physical", "down", "", "spare", "", "", nil, nil},
+		{"bridge0", "virtual-switch", "", "virtual-switch", "BRIDGE", "", "", []string{"igb4", "igb7"}, []string{"10.0.50.1/24"}},
+		{"igb4", "physical", "", "", "OPT4", "bridge0", "", nil, nil},
+		{"igb7", "physical", "", "", "", "bridge0", "", nil, nil},
+		{"lo0", "virtual", "", "", "Loopback", "", "", nil, []string{"127.0.0.1/8"}},
+	}
+	for _, tt := range tests {
+		iface := findInterface(t, interfaces, tt.name)
+		if iface.InterfaceType != tt.interfaceType || iface.Status != tt.status || iface.Description != tt.description || iface.Parent != tt.parent {
+			t.Errorf("%s: got type %q status %q description %q parent %q, want %q %q %q %q", tt.name,
+				iface.InterfaceType, iface.Status, iface.Description, iface.Parent, tt.interfaceType, tt.status, tt.description, tt.parent)
+		}
+		if iface.Label != tt.label || iface.VlanId != tt.vlanId || !slices.Equal(iface.Members, tt.members) {
+			t.Errorf("%s: got label %q vlan %q members %v, want %q %q %v", tt.name, iface.Label, iface.VlanId, iface.Members, tt.label, tt.vlanId, tt.members)
+		}
+		if !slices.Equal(iface.IPAddresses, tt.ipAddresses) {
+			t.Errorf("%s: got addresses %v, want %v", tt.name, iface.IPAddresses, tt.ipAddresses)
+		}
+	}
+
+	if len(*interfaces) != 12 {
+		t.Errorf("got %d interfaces, want 12", len(*interfaces))
+	}
+}
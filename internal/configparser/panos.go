@@ -0,0 +1,198 @@
+package configparser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const panosDefaultRouter = "default"
+
+type panosEntry struct {
+	Name string `xml:"name,attr"`
+}
+
+type panosUnit struct {
+	Name    string       `xml:"name,attr"`
+	Tag     string       `xml:"tag"`
+	Comment string       `xml:"comment"`
+	IP      []panosEntry `xml:"ip>entry"`
+	IPv6    []panosEntry `xml:"ipv6>address>entry"`
+}
+
+type panosLayer struct {
+	IP    []panosEntry `xml:"ip>entry"`
+	IPv6  []panosEntry `xml:"ipv6>address>entry"`
+	Units []panosUnit  `xml:"units>entry"`
+}
+
+type panosEthernet struct {
+	Name           string     `xml:"name,attr"`
+	Comment        string     `xml:"comment"`
+	LinkState      string     `xml:"link-state"`
+	AggregateGroup string     `xml:"aggregate-group"`
+	Layer3         panosLayer `xml:"layer3"`
+	Layer2         panosLayer `xml:"layer2"`
+}
+
+type panosMemberList struct {
+	Name    string   `xml:"name,attr"`
+	Members []string `xml:"interface>member"`
+}
+
+type panosZone struct {
+	Name        string   `xml:"name,attr"`
+	Layer3      []string `xml:"network>layer3>member"`
+	Layer2      []string `xml:"network>layer2>member"`
+	VirtualWire []string `xml:"network>virtual-wire>member"`
+	Tap         []string `xml:"network>tap>member"`
+}
+
+type panosDevice struct {
+	Ethernet       []panosEthernet   `xml:"network>interface>ethernet>entry"`
+	Aggregate      []panosEthernet   `xml:"network>interface>aggregate-ethernet>entry"`
+	Loopback       panosLayer        `xml:"network>interface>loopback"`
+	Tunnel         panosLayer        `xml:"network>interface>tunnel"`
+	Vlan           panosLayer        `xml:"network>interface>vlan"`
+	VirtualRouters []panosMemberList `xml:"network>virtual-router>entry"`
+	LogicalRouters []struct {
+		Vrfs []panosMemberList `xml:"vrf>entry"`
+	} `xml:"network>logical-router>entry"`
+	Vsys []struct {
+		Zones []panosZone `xml:"zone>entry"`
+	} `xml:"vsys>entry"`
+}
+
+type panosConfig struct {
+	XMLName xml.Name      `xml:"config"`
+	Devices []panosDevice `xml:"devices>entry"`
+}
+
+func ParsePanOSConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+
+	start := strings.Index(*config, "<config")
+	if start == -1 {
+		return &deviceInterfaces, fmt.Errorf("no PAN-OS xml config found")
+	}
+
+	var panos panosConfig
+	if err := xml.Unmarshal([]byte((*config)[start:]), &panos); err != nil {
+		return &deviceInterfaces, fmt.Errorf("could not parse PAN-OS xml: %s", err)
+	}
+
+	for _, device := range panos.Devices {
+		lagMembers := map[string][]string{}
+
+		for _, ethernet := range device.Ethernet {
+			var pyh model.FortigateInterface
+			pyh.InterfaceType = "physical"
+			pyh.Name = ethernet.Name
+			pyh.Description = ethernet.Comment
+			if ethernet.LinkState == "down" {
+				pyh.Status = "down"
+			}
+			pyh.IPAddresses = panosAddresses(ethernet.Layer3.IP, ethernet.Layer3.IPv6)
+			if ethernet.AggregateGroup != "" {
+				lagMembers[ethernet.AggregateGroup] = append(lagMembers[ethernet.AggregateGroup], ethernet.Name)
+			}
+			deviceInterfaces = append(deviceInterfaces, pyh)
+			deviceInterfaces = append(deviceInterfaces, panosUnits(ethernet.Name, ethernet.Layer3.Units, ethernet.Layer2.Units)...)
+		}
+
+		for _, aggregate := range device.Aggregate {
+			var aggr model.FortigateInterface
+			aggr.InterfaceType = "aggregate"
+			aggr.Name = aggregate.Name
+			aggr.Description = aggregate.Comment
+			aggr.IPAddresses = panosAddresses(aggregate.Layer3.IP, aggregate.Layer3.IPv6)
+			deviceInterfaces = append(deviceInterfaces, aggr)
+			deviceInterfaces = append(deviceInterfaces, panosUnits(aggregate.Name, aggregate.Layer3.Units, aggregate.Layer2.Units)...)
+		}
+
+		for _, logical := range []panosLayer{device.Loopback, device.Tunnel, device.Vlan} {
+			for _, unit := range logical.Units {
+				var virtual model.FortigateInterface
+				virtual.InterfaceType = "virtual"
+				virtual.Name = unit.Name
+				virtual.Description = unit.Comment
+				virtual.IPAddresses = panosAddresses(unit.IP, unit.IPv6)
+				deviceInterfaces = append(deviceInterfaces, virtual)
+			}
+		}
+
+		addLagMembers(&deviceInterfaces, lagMembers)
+
+		// the default virtual-router and vrf are the global routing table
+		vrfs := map[string]string{}
+		for _, router := range device.VirtualRouters {
+			if router.Name == panosDefaultRouter {
+				continue
+			}
+			for _, member := range router.Members {
+				vrfs[member] = router.Name
+			}
+		}
+		for _, router := range device.LogicalRouters {
+			for _, vrf := range router.Vrfs {
+				if vrf.Name == panosDefaultRouter {
+					continue
+				}
+				for _, member := range vrf.Members {
+					vrfs[member] = vrf.Name
+				}
+			}
+		}
+
+		zones := map[string]string{}
+		for _, vsys := range device.Vsys {
+			for _, zone := range vsys.Zones {
+				for _, members := range [][]string{zone.Layer3, zone.Layer2, zone.VirtualWire, zone.Tap} {
+					for _, member := range members {
+						zones[member] = zone.Name
+					}
+				}
+			}
+		}
+
+		for index, iface := range deviceInterfaces {
+			deviceInterfaces[index].Vrf = vrfs[iface.Name]
+			deviceInterfaces[index].Zone = zones[iface.Name]
+		}
+	}
+
+	return &deviceInterfaces, nil
+}
+
+func panosUnits(parent string, layer3 []panosUnit, layer2 []panosUnit) []model.FortigateInterface {
+	var result []model.FortigateInterface
+	for _, unit := range slices.Concat(layer3, layer2) {
+		var child model.FortigateInterface
+		child.Name = unit.Name
+		child.Parent = parent
+		child.Description = unit.Comment
+		child.IPAddresses = panosAddresses(unit.IP, unit.IPv6)
+		if unit.Tag != "" {
+			child.InterfaceType = "vlan"
+			child.VlanId = unit.Tag
+		} else {
+			child.InterfaceType = "virtual"
+		}
+		result = append(result, child)
+	}
+	return result
+}
+
+// panosAddresses returns the addresses in prefix notation, address objects are skipped.
+func panosAddresses(ipv4 []panosEntry, ipv6 []panosEntry) []string {
+	var result []string
+	for _, entry := range slices.Concat(ipv4, ipv6) {
+		if strings.Contains(entry.Name, "/") {
+			result = append(result, entry.Name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+)
+
+const panosFixture = `<?xml version="1.0"?>
+<config version="10.2.0" urldb="paloaltonetworks">
+  <devices>
+    <entry name="localhost.localdomain">
+      <network>
+        <interface>
+          <ethernet>
+            <entry name="ethernet1/1">
+              <layer3>
+                <ip>
+                  <entry name="192.0.2.2/24"/>
+                  <entry name="fw-untrust-address"/>
+                </ip>
+              </layer3>
+              <comment>internet</comment>
+            </entry>
+            <entry name="ethernet1/2">
+              <layer3>
+                <units>
+                  <entry name="ethernet1/2.100">
+                    <tag>100</tag>
+                    <ip>
+                      <entry name="10.0.100.1/24"/>
+                    </ip>
+                    <ipv6>
+                      <address>
+                        <entry name="2001:db8:100::1/64"/>
+                      </address>
+                    </ipv6>
+                    <comment>servers</comment>
+                  </entry>
+                </units>
+              </layer3>
+            </entry>
+            <entry name="ethernet1/3">
+              <aggregate-group>ae1</aggregate-group>
+            </entry>
+            <entry name="ethernet1/4">
+              <aggregate-group>ae1</aggregate-group>
+              <link-state>down</link-state>
+            </entry>
+          </ethernet>
+          <aggregate-ethernet>
+            <entry name="ae1">
+              <layer2>
+                <units>
+                  <entry name="ae1.200">
+                    <tag>200</tag>
+                  </entry>
+                </units>
+              </layer2>
+              <comment>to core</comment>
+            </entry>
+          </aggregate-ethernet>
+          <loopback>
+            <units>
+              <entry name="loopback.1">
+                <ip>
+                  <entry name="10.255.0.1/32"/>
+                </ip>
+              </entry>
+            </units>
+          </loopback>
+          <tunnel>
+            <units>
+              <entry name="tunnel.1">
+                <comment>ipsec to branch</comment>
+              </entry>
+            </units>
+          </tunnel>
+        </interface>
+        <virtual-router>
+          <entry name="default">
+            <interface>
+              <member>ethernet1/1</member>
+              <member>loopback.1</member>
+            </interface>
+          </entry>
+          <entry name="CUST">
+            <interface>
+              <member>ethernet1/2.100</member>
+              <member>tunnel.1</member>
+            </interface>
+          </entry>
+        </virtual-router>
+      </network>
+      <vsys>
+        <entry name="vsys1">
+          <zone>
+            <entry name="untrust">
+              <network>
+                <layer3>
+                  <member>ethernet1/1</member>
+                </layer3>
+              </network>
+            </entry>
+            <entry name="servers">
+              <network>
+                <layer3>
+                  <member>ethernet1/2.100</member>
+                </layer3>
+              </network>
+            </entry>
+            <entry name="core">
+              <network>
+                <layer2>
+                  <member>ae1.200</member>
+                </layer2>
+              </network>
+            </entry>
+          </zone>
+        </entry>
+      </vsys>
+    </entry>
+  </devices>
+</config>
+`
+
+func TestParsePanOSConfig(t *testing.T) {
+	config := panosFixture
+	interfaces, err := ParsePanOSConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		interfaceType string
+		status        string
+		description   string
+		parent        string
+		vlanId        string
+		members       []string
+		vrf           string
+		zone          string
+		ipAddresses   []string
+	}{
+		{"ethernet1/1", "physical", "", "internet", "", "", nil, "", "untrust", []string{"192.0.2.2/24"}},
+		{"ethernet1/2", "physical", "", "", "", "", nil, "", "", nil},
+		{"ethernet1/2.100", "vlan", "", "servers", "ethernet1/2", "100", nil, "CUST", "servers", []string{"10.0.100.1/24", "2001:db8:100::1/64"}},
+		{"ethernet1/3", "physical", "", "", "", "", nil, "", "", nil},
+		{"ethernet1/4", "physical", "down", "", "", "", nil, "", "", nil},
+		{"ae1", "aggregate", "", "to core", "", "", []string{"ethernet1/3", "ethernet1/4"}, "", "", nil},
+		{"ae1.200", "vlan", "", "", "ae1", "200", nil, "", "core", nil},
+		{"loopback.1", "virtual", "", "", "", "", nil, "", "", []string{"10.255.0.1/32"}},
+		{"tunnel.1", "virtual", "", "ipsec to branch", "", "", nil, "CUST", "", nil},
+	}
+	for _, tt := range tests {
+		iface := findInterface(t, interfaces, tt.name)
+		if iface.InterfaceType != tt.interfaceType || iface.Status != tt.status || iface.Description != tt.description || iface.Parent != tt.parent {
+			t.Errorf("%s: got type %q status %q description %q parent %q, want %q %q %q %q", tt.name,
+				iface.InterfaceType, iface.Status, iface.Description, iface.Parent, tt.interfaceType, tt.status, tt.description, tt.parent)
+		}
+		if iface.VlanId != tt.vlanId || !slices.Equal(iface.Members, tt.members) {
+			t.Errorf("%s: got vlan %q members %v, want %q %v", tt.name, iface.VlanId, iface.Members, tt.vlanId, tt.members)
+		}
+		if iface.Vrf != tt.vrf || iface.Zone != tt.zone || !slices.Equal(iface.IPAddresses, tt.ipAddresses) {
+			t.Errorf("%s: got vrf %q zone %q addresses %v, want %q %q %v", tt.name,
+				iface.Vrf, iface.Zone, iface.IPAddresses, tt.vrf, tt.zone, tt.ipAddresses)
+		}
+	}
+
+	if len(*interfaces) != len(tests) {
+		t.Errorf("got %d interfaces, want %d", len(*interfaces), len(tests))
+	}
+}
+
+func TestParsePanOSConfigWithoutXML(t *testing.T) {
+	config := "set deviceconfig system hostname fw1"
+	if _, err := ParsePanOSConfig(&config); err == nil {
+		t.Error("expected an error for a config without xml")
+	}
+}
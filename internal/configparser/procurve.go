@@ -0,0 +1,163 @@
+package configparser
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	procurveName      = "name "
+	procurveDisable   = "disable"
+	procurveTrunk     = "trunk "
+	procurveTagged    = "tagged "
+	procurveUntagged  = "untagged "
+	procurveIPAddress = "ip address "
+	procurveVlan      = "vlan "
+)
+
+var procurvePortName = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+func ParseProcurveConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+	portIndex := map[string]int{}
+
+	getPort := func(name string) *model.FortigateInterface {
+		if index, ok := portIndex[strings.ToLower(name)]; ok {
+			return &deviceInterfaces[index]
+		}
+		var port model.FortigateInterface
+		port.Name = name
+		port.InterfaceType = "physical"
+		if strings.HasPrefix(strings.ToLower(name), "trk") {
+			port.InterfaceType = "aggregate"
+		}
+		deviceInterfaces = append(deviceInterfaces, port)
+		portIndex[strings.ToLower(name)] = len(deviceInterfaces) - 1
+		return &deviceInterfaces[len(deviceInterfaces)-1]
+	}
+
+	for _, section := range parseIndentedSections(config, iosInterfacePrefix) {
+		port := getPort(normalizeProcurvePortName(section.header))
+		for _, line := range section.lines {
+			switch {
+			case strings.HasPrefix(line, procurveName):
+				port.Description = strings.Trim(strings.TrimPrefix(line, procurveName), "\"")
+			case line == procurveDisable:
+				port.Status = "down"
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(*config))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, procurveTrunk) {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, procurveTrunk))
+		if len(fields) < 2 {
+			continue
+		}
+		members := expandProcurvePortList(fields[0])
+		for _, member := range members {
+			getPort(member)
+		}
+		lag := getPort(normalizeProcurvePortName(fields[1]))
+		lag.Members = append(lag.Members, members...)
+	}
+
+	// vlan membership is configured per vlan, pivot it to the ports
+	var vlanInterfaces []model.FortigateInterface
+	for _, section := range parseIndentedSections(config, procurveVlan) {
+		vlanId, _, _ := strings.Cut(section.header, " ")
+		if _, err := strconv.Atoi(vlanId); err != nil {
+			continue
+		}
+
+		var vlanInterface model.FortigateInterface
+		for _, line := range section.lines {
+			switch {
+			case strings.HasPrefix(line, procurveName):
+				vlanInterface.Description = strings.Trim(strings.TrimPrefix(line, procurveName), "\"")
+			case strings.HasPrefix(line, procurveUntagged):
+				for _, portName := range expandProcurvePortList(strings.TrimPrefix(line, procurveUntagged)) {
+					port := getPort(portName)
+					port.VlanId = vlanId
+					if port.VlanMode == "" {
+						port.VlanMode = vlanModeAccess
+					}
+				}
+			case strings.HasPrefix(line, procurveTagged):
+				for _, portName := range expandProcurvePortList(strings.TrimPrefix(line, procurveTagged)) {
+					port := getPort(portName)
+					port.VlanMode = vlanModeTagged
+					port.TaggedVlans = append(port.TaggedVlans, vlanId)
+				}
+			case strings.HasPrefix(line, procurveIPAddress):
+				addressFields := strings.Fields(strings.TrimPrefix(line, procurveIPAddress))
+				if len(addressFields) == 2 {
+					if address := maskToCIDR(addressFields[0], addressFields[1]); address != "" {
+						vlanInterface.IPAddresses = append(vlanInterface.IPAddresses, address)
+					}
+				} else if len(addressFields) == 1 && strings.Contains(addressFields[0], "/") {
+					vlanInterface.IPAddresses = append(vlanInterface.IPAddresses, addressFields[0])
+				}
+			}
+		}
+
+		if len(vlanInterface.IPAddresses) != 0 {
+			vlanInterface.Name = "vlan" + vlanId
+			vlanInterface.InterfaceType = "vlan"
+			vlanInterface.VlanId = vlanId
+			vlanInterfaces = append(vlanInterfaces, vlanInterface)
+		}
+	}
+
+	deviceInterfaces = append(deviceInterfaces, vlanInterfaces...)
+
+	return &deviceInterfaces, nil
+}
+
+// normalizeProcurvePortName writes trunks the way they are shown in vlan member lists (Trk1).
+func normalizeProcurvePortName(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.HasPrefix(strings.ToLower(name), "trk") {
+		return "Trk" + name[3:]
+	}
+	return name
+}
+
+// expandProcurvePortList expands port lists like "1-4,A1-A2,Trk1" to the separate ports.
+func expandProcurvePortList(list string) []string {
+	var result []string
+
+	for _, element := range strings.Split(strings.TrimSpace(list), ",") {
+		element = normalizeProcurvePortName(element)
+		if element == "" {
+			continue
+		}
+
+		start, end, isRange := strings.Cut(element, "-")
+		if !isRange {
+			result = append(result, element)
+			continue
+		}
+
+		startMatch := procurvePortName.FindStringSubmatch(start)
+		endMatch := procurvePortName.FindStringSubmatch(normalizeProcurvePortName(end))
+		if startMatch == nil || endMatch == nil || startMatch[1] != endMatch[1] {
+			continue
+		}
+		first, _ := strconv.Atoi(startMatch[2])
+		last, _ := strconv.Atoi(endMatch[2])
+		for number := first; number <= last; number++ {
+			result = append(result, startMatch[1]+strconv.Itoa(number))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,101 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+)
+
+const procurveFixture = `; J9729A Configuration Editor; Created on release #WB.16.10.0012
+hostname "sw1"
+trunk 23-24 trk1 lacp
+interface 1
+   name "server a"
+   exit
+interface 2
+   disable
+   exit
+interface Trk1
+   name "uplink"
+   exit
+vlan 1
+   name "DEFAULT_VLAN"
+   untagged 3-22
+   no untagged 1-2,Trk1
+   ip address dhcp-bootp
+   exit
+vlan 10
+   name "servers"
+   untagged 1-2
+   tagged Trk1
+   ip address 10.0.10.2 255.255.255.0
+   exit
+vlan 20
+   name "voice"
+   tagged 1,Trk1
+   ip address 10.0.20.2/24
+   exit
+`
+
+func TestParseProcurveConfig(t *testing.T) {
+	config := procurveFixture
+	interfaces, err := ParseProcurveConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		interfaceType string
+		status        string
+		description   string
+		vlanMode      string
+		vlanId        string
+		taggedVlans   []string
+		members       []string
+		ipAddresses   []string
+	}{
+		{"1", "physical", "", "server a", vlanModeTagged, "10", []string{"20"}, nil, nil},
+		{"2", "physical", "down", "", vlanModeAccess, "10", nil, nil, nil},
+		{"3", "physical", "", "", vlanModeAccess, "1", nil, nil, nil},
+		{"22", "physical", "", "", vlanModeAccess, "1", nil, nil, nil},
+		{"23", "physical", "", "", "", "", nil, nil, nil},
+		{"Trk1", "aggregate", "", "uplink", vlanModeTagged, "", []string{"10", "20"}, []string{"23", "24"}, nil},
+		{"vlan10", "vlan", "", "servers", "", "10", nil, nil, []string{"10.0.10.2/24"}},
+		{"vlan20", "vlan", "", "voice", "", "20", nil, nil, []string{"10.0.20.2/24"}},
+	}
+	for _, tt := range tests {
+		iface := findInterface(t, interfaces, tt.name)
+		if iface.InterfaceType != tt.interfaceType || iface.Status != tt.status || iface.Description != tt.description {
+			t.Errorf("%s: got type %q status %q description %q, want %q %q %q", tt.name, iface.InterfaceType, iface.Status, iface.Description, tt.interfaceType, tt.status, tt.description)
+		}
+		if iface.VlanMode != tt.vlanMode || iface.VlanId != tt.vlanId || !slices.Equal(iface.TaggedVlans, tt.taggedVlans) {
+			t.Errorf("%s: got mode %q vlan %q tagged %v, want %q %q %v", tt.name, iface.VlanMode, iface.VlanId, iface.TaggedVlans, tt.vlanMode, tt.vlanId, tt.taggedVlans)
+		}
+		if !slices.Equal(iface.Members, tt.members) || !slices.Equal(iface.IPAddresses, tt.ipAddresses) {
+			t.Errorf("%s: got members %v addresses %v, want %v %v", tt.name, iface.Members, iface.IPAddresses, tt.members, tt.ipAddresses)
+		}
+	}
+
+	// 24 ports, the trunk and the two vlan interfaces with an address
+	if len(*interfaces) != 27 {
+		t.Errorf("got %d interfaces, want 27", len(*interfaces))
+	}
+}
+
+func TestExpandProcurvePortList(t *testing.T) {
+	tests := []struct {
+		list string
+		want []string
+	}{
+		{"1-3", []string{"1", "2", "3"}},
+		{"A1-A2,B4", []string{"A1", "A2", "B4"}},
+		{"trk1,Trk2", []string{"Trk1", "Trk2"}},
+		{"1/1-1/3", []string{"1/1", "1/2", "1/3"}},
+		{"A1-B2", nil},
+	}
+	for _, tt := range tests {
+		if got := expandProcurvePortList(tt.list); !slices.Equal(got, tt.want) {
+			t.Errorf("expandProcurvePortList(%q) = %v, want %v", tt.list, got, tt.want)
+		}
+	}
+}
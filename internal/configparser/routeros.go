@@ -0,0 +1,198 @@
+package configparser
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	routerosEthernet   = "/interface ethernet"
+	routerosBonding    = "/interface bonding"
+	routerosBridge     = "/interface bridge"
+	routerosBridgePort = "/interface bridge port"
+	routerosVlan       = "/interface vlan"
+	routerosIPAddress  = "/ip address"
+	routerosIPv6       = "/ipv6 address"
+)
+
+type routerosCommand struct {
+	section string
+	values  map[string]string
+}
+
+func ParseRouterOSConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+	var bridges []model.FortigateVirtualSwitch
+	addresses := map[string][]string{}
+
+	for _, command := range parseRouterOSExport(config) {
+		values := command.values
+		switch command.section {
+		case routerosEthernet:
+			var eth model.FortigateInterface
+			eth.InterfaceType = "physical"
+			eth.Name = values["name"]
+			if eth.Name == "" {
+				eth.Name = values["default-name"]
+			}
+			eth.Description = values["comment"]
+			eth.Status = routerosStatus(values)
+			deviceInterfaces = append(deviceInterfaces, eth)
+		case routerosBonding:
+			var aggr model.FortigateInterface
+			aggr.InterfaceType = "aggregate"
+			aggr.Name = values["name"]
+			aggr.Description = values["comment"]
+			aggr.Status = routerosStatus(values)
+			for _, slave := range strings.Split(values["slaves"], ",") {
+				if slave != "" {
+					aggr.Members = append(aggr.Members, slave)
+				}
+			}
+			deviceInterfaces = append(deviceInterfaces, aggr)
+		case routerosBridge:
+			var vSwitch model.FortigateVirtualSwitch
+			vSwitch.Name = values["name"]
+			bridges = append(bridges, vSwitch)
+		case routerosBridgePort:
+			for index, bridge := range bridges {
+				if bridge.Name == values["bridge"] {
+					bridges[index].Members = append(bridges[index].Members, values["interface"])
+				}
+			}
+		case routerosVlan:
+			var vid model.FortigateInterface
+			vid.InterfaceType = "vlan"
+			vid.Name = values["name"]
+			vid.Description = values["comment"]
+			vid.Status = routerosStatus(values)
+			vid.VlanId = values["vlan-id"]
+			vid.Parent = values["interface"]
+			deviceInterfaces = append(deviceInterfaces, vid)
+		case routerosIPAddress, routerosIPv6:
+			if values["interface"] != "" && values["address"] != "" {
+				addresses[values["interface"]] = append(addresses[values["interface"]], values["address"])
+			}
+		}
+	}
+
+	ensureRouterOSPorts(&deviceInterfaces, &bridges)
+	convertVirtualSwitch(&bridges, &deviceInterfaces)
+
+	for index, iface := range deviceInterfaces {
+		deviceInterfaces[index].IPAddresses = addresses[iface.Name]
+	}
+
+	return &deviceInterfaces, nil
+}
+
+// ensureRouterOSPorts adds the default ethernet ports that are only referenced
+// by bonding or bridge ports, unchanged ports are not part of the export.
+func ensureRouterOSPorts(deviceInterfaces *[]model.FortigateInterface, bridges *[]model.FortigateVirtualSwitch) {
+	known := map[string]bool{}
+	for _, iface := range *deviceInterfaces {
+		known[iface.Name] = true
+	}
+	for _, bridge := range *bridges {
+		known[bridge.Name] = true
+	}
+
+	var referenced []string
+	for _, iface := range *deviceInterfaces {
+		referenced = append(referenced, iface.Members...)
+	}
+	for _, bridge := range *bridges {
+		referenced = append(referenced, bridge.Members...)
+	}
+
+	for _, name := range referenced {
+		if known[name] {
+			continue
+		}
+		if strings.HasPrefix(name, "ether") || strings.HasPrefix(name, "sfp") {
+			var eth model.FortigateInterface
+			eth.InterfaceType = "physical"
+			eth.Name = name
+			*deviceInterfaces = append(*deviceInterfaces, eth)
+			known[name] = true
+		}
+	}
+}
+
+func routerosStatus(values map[string]string) string {
+	if values["disabled"] == "yes" {
+		return "down"
+	}
+	return ""
+}
+
+// parseRouterOSExport splits an /export into add/set commands with their key=value pairs.
+func parseRouterOSExport(config *string) []routerosCommand {
+	var (
+		commands []routerosCommand
+		section  string
+		pending  string
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(*config))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if strings.HasSuffix(line, "\\") {
+			pending += strings.TrimSuffix(line, "\\")
+			continue
+		}
+		line = strings.TrimSpace(pending + line)
+		pending = ""
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "/") {
+			section = line
+			continue
+		}
+
+		action, arguments, _ := strings.Cut(line, " ")
+		if action != "add" && action != "set" {
+			continue
+		}
+		commands = append(commands, routerosCommand{section: section, values: parseRouterOSValues(arguments)})
+	}
+
+	return commands
+}
+
+// parseRouterOSValues parses key=value pairs, including quoted values and "[ find key=value ]" selectors.
+func parseRouterOSValues(arguments string) map[string]string {
+	values := map[string]string{}
+
+	var (
+		token    strings.Builder
+		inQuotes bool
+	)
+	store := func() {
+		key, value, found := strings.Cut(token.String(), "=")
+		if found {
+			values[key] = value
+		}
+		token.Reset()
+	}
+
+	for _, char := range arguments {
+		switch {
+		case char == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+			token.WriteRune(char)
+		case char == ' ' || char == '[' || char == ']':
+			store()
+		default:
+			token.WriteRune(char)
+		}
+	}
+	store()
+
+	return values
+}
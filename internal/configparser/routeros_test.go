@@ -0,0 +1,87 @@
+package configparser
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+const routerosFixture = `# jan/02/2024 10:00:00 by RouterOS 7.12
+# software id = ABCD-1234
+#
+# model = CCR2004-16G-2S+
+/interface bridge
+add comment="lan bridge" name=bridge1 vlan-filtering=yes
+/interface ethernet
+set [ find default-name=ether1 ] comment="wan uplink" name=ether1-wan
+set [ find default-name=ether2 ] disabled=yes
+set [ find default-name=sfp-sfpplus1 ] advertise=10M-half,10M-full,100M-half,100M-full,1G-half,1G-full \\
+    comment="to core"
+/interface bonding
+add comment=servers mode=802.3ad name=bond1 slaves=ether3,ether4 \\
+    transmit-hash-policy=layer-2-and-3
+/interface vlan
+add interface=bond1 name=vlan100 vlan-id=100
+add comment="guest wifi" disabled=yes interface=bridge1 name=vlan200 vlan-id=200
+/interface bridge port
+add bridge=bridge1 interface=ether5
+add bridge=bridge1 interface=ether6
+/ip address
+add address=192.0.2.2/24 interface=ether1-wan network=192.0.2.0
+add address=10.0.100.1/24 comment=servers interface=vlan100 network=10.0.100.0
+/ipv6 address
+add address=2001:db8:100::1/64 advertise=no interface=vlan100
+`
+
+func TestParseRouterOSConfig(t *testing.T) {
+	config := routerosFixture
+	interfaces, err := ParseRouterOSConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		interfaceType string
+		status        string
+		description   string
+		parent        string
+		vlanId        string
+		members       []string
+		ipAddresses   []string
+	}{
+		{"ether1-wan", "physical", "", "wan uplink", "", "", nil, []string{"192.0.2.2/24"}},
+		{"ether2", "physical", "down", "", "", "", nil, nil},
+		{"sfp-sfpplus1", "physical", "", "to core", "", "", nil, nil},
+		{"bond1", "aggregate", "", "servers", "", "", []string{"ether3", "ether4"}, nil},
+		{"ether3", "physical", "", "", "", "", nil, nil},
+		{"vlan100", "vlan", "", "", "bond1", "100", nil, []string{"10.0.100.1/24", "2001:db8:100::1/64"}},
+		{"vlan200", "vlan", "down", "guest wifi", "bridge1", "200", nil, nil},
+		{"bridge1", "virtual-switch", "", "virtual-switch", "", "", []string{"ether5", "ether6"}, nil},
+		{"ether5", "physical", "", "", "bridge1", "", nil, nil},
+	}
+	for _, tt := range tests {
+		iface := findInterface(t, interfaces, tt.name)
+		if iface.InterfaceType != tt.interfaceType || iface.Status != tt.status || iface.Description != tt.description || iface.Parent != tt.parent {
+			t.Errorf("%s: got type %q status %q description %q parent %q, want %q %q %q %q", tt.name,
+				iface.InterfaceType, iface.Status, iface.Description, iface.Parent, tt.interfaceType, tt.status, tt.description, tt.parent)
+		}
+		if iface.VlanId != tt.vlanId || !slices.Equal(iface.Members, tt.members) || !slices.Equal(iface.IPAddresses, tt.ipAddresses) {
+			t.Errorf("%s: got vlan %q members %v addresses %v, want %q %v %v", tt.name,
+				iface.VlanId, iface.Members, iface.IPAddresses, tt.vlanId, tt.members, tt.ipAddresses)
+		}
+	}
+
+	// the three ethernet ports, the bond, two vlans, the bridge and the four ports only used as member
+	if len(*interfaces) != 11 {
+		t.Errorf("got %d interfaces, want 11", len(*interfaces))
+	}
+}
+
+func TestParseRouterOSValues(t *testing.T) {
+	got := parseRouterOSValues(`[ find default-name=ether1 ] comment="wan link" name=ether1-wan`)
+	want := map[string]string{"default-name": "ether1", "comment": "wan link", "name": "ether1-wan"}
+	if !maps.Equal(got, want) {
+		t.Errorf("parseRouterOSValues() = %v, want %v", got, want)
+	}
+}
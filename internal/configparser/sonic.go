@@ -0,0 +1,185 @@
+package configparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	sonicVlanName     = "Vlan"
+	sonicUntagged     = "untagged"
+	sonicAdminDown    = "down"
+	sonicKeySeparator = "|"
+)
+
+// sonicTable is a table of config_db.json, the values are mostly strings but some tables use lists.
+type sonicTable map[string]map[string]any
+
+type sonicConfig struct {
+	Port                 sonicTable `json:"PORT"`
+	PortChannel          sonicTable `json:"PORTCHANNEL"`
+	PortChannelMember    sonicTable `json:"PORTCHANNEL_MEMBER"`
+	Vlan                 sonicTable `json:"VLAN"`
+	VlanMember           sonicTable `json:"VLAN_MEMBER"`
+	VlanSubInterface     sonicTable `json:"VLAN_SUB_INTERFACE"`
+	Interface            sonicTable `json:"INTERFACE"`
+	PortChannelInterface sonicTable `json:"PORTCHANNEL_INTERFACE"`
+	VlanInterface        sonicTable `json:"VLAN_INTERFACE"`
+	LoopbackInterface    sonicTable `json:"LOOPBACK_INTERFACE"`
+}
+
+// ParseSONiCConfig parses the config_db.json of SONiC.
+func ParseSONiCConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+
+	start := strings.Index(*config, "{")
+	if start == -1 {
+		return &deviceInterfaces, fmt.Errorf("no config_db.json found")
+	}
+
+	var sonic sonicConfig
+	if err := json.Unmarshal([]byte((*config)[start:]), &sonic); err != nil {
+		return &deviceInterfaces, fmt.Errorf("could not parse config_db.json: %s", err)
+	}
+
+	for _, name := range sonic.Port.keys() {
+		port := sonic.Port[name]
+		var pyh model.FortigateInterface
+		pyh.InterfaceType = "physical"
+		pyh.Name = name
+		pyh.Description = sonicValue(port, "description")
+		pyh.Label = sonicValue(port, "alias")
+		pyh.Speed = sonicValue(port, "speed")
+		if sonicValue(port, "admin_status") == sonicAdminDown {
+			pyh.Status = "down"
+		}
+		deviceInterfaces = append(deviceInterfaces, pyh)
+	}
+
+	for _, name := range sonic.PortChannel.keys() {
+		portChannel := sonic.PortChannel[name]
+		var aggr model.FortigateInterface
+		aggr.InterfaceType = "aggregate"
+		aggr.Name = name
+		aggr.Description = sonicValue(portChannel, "description")
+		if sonicValue(portChannel, "admin_status") == sonicAdminDown {
+			aggr.Status = "down"
+		}
+		deviceInterfaces = append(deviceInterfaces, aggr)
+	}
+
+	lagMembers := map[string][]string{}
+	for _, key := range sonic.PortChannelMember.keys() {
+		if lag, member, ok := strings.Cut(key, sonicKeySeparator); ok {
+			lagMembers[lag] = append(lagMembers[lag], member)
+		}
+	}
+	addLagMembers(&deviceInterfaces, lagMembers)
+
+	vlanIds := map[string]string{}
+	for _, name := range sonic.Vlan.keys() {
+		vlanIds[name] = sonicValue(sonic.Vlan[name], "vlanid")
+		if vlanIds[name] == "" {
+			vlanIds[name] = strings.TrimPrefix(name, sonicVlanName)
+		}
+	}
+
+	for _, key := range sonic.VlanMember.keys() {
+		vlan, member, ok := strings.Cut(key, sonicKeySeparator)
+		if !ok {
+			continue
+		}
+		vlanId := vlanIds[vlan]
+		if vlanId == "" {
+			vlanId = strings.TrimPrefix(vlan, sonicVlanName)
+		}
+		for index, iface := range deviceInterfaces {
+			if iface.Name != member {
+				continue
+			}
+			if sonicValue(sonic.VlanMember[key], "tagging_mode") == sonicUntagged {
+				deviceInterfaces[index].VlanId = vlanId
+			} else {
+				deviceInterfaces[index].TaggedVlans = append(deviceInterfaces[index].TaggedVlans, vlanId)
+			}
+			deviceInterfaces[index].VlanMode = vlanModeAccess
+			if len(deviceInterfaces[index].TaggedVlans) > 0 {
+				deviceInterfaces[index].VlanMode = vlanModeTagged
+			}
+		}
+	}
+
+	for _, key := range sonic.VlanSubInterface.keys() {
+		if strings.Contains(key, sonicKeySeparator) {
+			continue
+		}
+		subinterface := sonic.VlanSubInterface[key]
+		var vid model.FortigateInterface
+		vid.InterfaceType = "vlan"
+		vid.Name = key
+		vid.Parent, vid.VlanId, _ = strings.Cut(key, ".")
+		if vlan := sonicValue(subinterface, "vlan"); vlan != "" {
+			vid.VlanId = vlan
+		}
+		vid.Vrf = sonicValue(subinterface, "vrf_name")
+		if sonicValue(subinterface, "admin_status") == sonicAdminDown {
+			vid.Status = "down"
+		}
+		deviceInterfaces = append(deviceInterfaces, vid)
+	}
+
+	// the layer 3 tables have an entry per interface with the vrf and an entry per address
+	for _, table := range []sonicTable{sonic.Interface, sonic.PortChannelInterface, sonic.VlanInterface, sonic.LoopbackInterface, sonic.VlanSubInterface} {
+		for _, key := range table.keys() {
+			name, address, hasAddress := strings.Cut(key, sonicKeySeparator)
+
+			index := -1
+			for i, iface := range deviceInterfaces {
+				if iface.Name == name {
+					index = i
+					break
+				}
+			}
+			if index == -1 {
+				var iface model.FortigateInterface
+				iface.Name = name
+				iface.InterfaceType = "virtual"
+				if vlanId, ok := vlanIds[name]; ok {
+					iface.InterfaceType = "vlan"
+					iface.VlanId = vlanId
+				}
+				deviceInterfaces = append(deviceInterfaces, iface)
+				index = len(deviceInterfaces) - 1
+			}
+
+			if hasAddress {
+				deviceInterfaces[index].IPAddresses = append(deviceInterfaces[index].IPAddresses, address)
+			} else if vrf := sonicValue(table[key], "vrf_name"); vrf != "" {
+				deviceInterfaces[index].Vrf = vrf
+			}
+		}
+	}
+
+	return &deviceInterfaces, nil
+}
+
+// keys returns the keys of the table sorted, so the interfaces are always created in the same order.
+func (table sonicTable) keys() []string {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sonicValue(entry map[string]any, key string) string {
+	if value, ok := entry[key].(string); ok {
+		return value
+	}
+	return ""
+}
@@ -0,0 +1,176 @@
+package configparser
+
+import (
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	vrpUndo          = "undo "
+	vrpLinkType      = "port link-type "
+	vrpTrunkPvid     = "port trunk pvid vlan "
+	vrpIPAddress     = "ip address "
+	vrpIPv6Address   = "ipv6 address "
+	vrpBindVpn       = "ip binding vpn-instance "
+	vrpLoopbackName  = "LoopBack"
+	vrpNullName      = "NULL"
+	vrpTunnelName    = "Tunnel"
+	vrpLinkTypeTrunk = "trunk"
+)
+
+// vrpDialect holds the keywords that differ between Huawei VRP and HPE Comware,
+// both share the same interface config layout.
+type vrpDialect struct {
+	lagMember         string
+	lagName           string
+	aggregatePrefixes []string
+	trunkAllowed      string
+	accessVlan        string
+	vlanInterface     string
+	subinterfaceVid   string
+}
+
+var huaweiVRP = vrpDialect{
+	lagMember:         "eth-trunk ",
+	lagName:           "Eth-Trunk",
+	aggregatePrefixes: []string{"Eth-Trunk"},
+	trunkAllowed:      "port trunk allow-pass vlan ",
+	accessVlan:        "port default vlan ",
+	vlanInterface:     "Vlanif",
+	subinterfaceVid:   "dot1q termination vid ",
+}
+
+func ParseVRPConfig(config *string) (*[]model.FortigateInterface, error) {
+	return parseVRPStyleConfig(config, huaweiVRP)
+}
+
+func parseVRPStyleConfig(config *string, dialect vrpDialect) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+	lagMembers := map[string][]string{}
+
+	for _, section := range parseIndentedSections(config, iosInterfacePrefix) {
+		iface, lag := parseVRPInterface(section, dialect)
+		if iface.Name == "" {
+			continue
+		}
+		if lag != "" {
+			lagMembers[lag] = append(lagMembers[lag], iface.Name)
+		}
+		deviceInterfaces = append(deviceInterfaces, iface)
+	}
+
+	addLagMembers(&deviceInterfaces, lagMembers)
+
+	return &deviceInterfaces, nil
+}
+
+// vrpVlanList converts "10 20 to 30" to the "10 20-30" form used by expandVlanList.
+func vrpVlanList(list string) string {
+	return strings.ReplaceAll(list, " to ", "-")
+}
+
+func parseVRPInterface(section configSection, dialect vrpDialect) (model.FortigateInterface, string) {
+	var iface model.FortigateInterface
+	var lag, linkType, accessVlan, nativeVlan string
+	var allowedVlans []string
+	allowAll := false
+
+	fields := strings.Fields(section.header)
+	if len(fields) == 0 {
+		return iface, ""
+	}
+	iface.Name = fields[0]
+
+	for _, line := range section.lines {
+		switch {
+		case strings.HasPrefix(line, iosDescription):
+			iface.Description = strings.TrimPrefix(line, iosDescription)
+		case line == iosShutdown:
+			iface.Status = "down"
+		case strings.HasPrefix(line, dialect.lagMember):
+			lag = dialect.lagName + strings.Fields(strings.TrimPrefix(line, dialect.lagMember))[0]
+		case strings.HasPrefix(line, vrpLinkType):
+			linkType = strings.TrimPrefix(line, vrpLinkType)
+		case strings.HasPrefix(line, dialect.accessVlan):
+			accessVlan = strings.TrimPrefix(line, dialect.accessVlan)
+		case strings.HasPrefix(line, vrpTrunkPvid):
+			nativeVlan = strings.TrimPrefix(line, vrpTrunkPvid)
+		case strings.HasPrefix(line, dialect.trunkAllowed):
+			allowed := vrpVlanList(strings.TrimPrefix(line, dialect.trunkAllowed))
+			if allowed == "all" {
+				allowAll = true
+			} else {
+				allowedVlans, _ = applyVlanListCommand(allowedVlans, "add "+allowed)
+			}
+		case strings.HasPrefix(line, vrpUndo+dialect.trunkAllowed):
+			removed := vrpVlanList(strings.TrimPrefix(line, vrpUndo+dialect.trunkAllowed))
+			allowedVlans, _ = applyVlanListCommand(allowedVlans, "remove "+removed)
+		case strings.HasPrefix(line, dialect.subinterfaceVid):
+			iface.VlanId = strings.Fields(strings.TrimPrefix(line, dialect.subinterfaceVid))[0]
+		case strings.HasPrefix(line, vrpBindVpn):
+			iface.Vrf = strings.TrimPrefix(line, vrpBindVpn)
+		case strings.HasPrefix(line, vrpIPAddress):
+			addressFields := strings.Fields(strings.TrimPrefix(line, vrpIPAddress))
+			if len(addressFields) >= 2 {
+				if address := maskToCIDR(addressFields[0], addressFields[1]); address != "" {
+					iface.IPAddresses = append(iface.IPAddresses, address)
+				}
+			}
+		case strings.HasPrefix(line, vrpIPv6Address):
+			addressFields := strings.Fields(strings.TrimPrefix(line, vrpIPv6Address))
+			if len(addressFields) > 0 && strings.Contains(addressFields[0], "/") {
+				iface.IPAddresses = append(iface.IPAddresses, addressFields[0])
+			}
+		}
+	}
+
+	// Comware ports are access ports by default and only show "port access vlan"
+	if linkType == "" && accessVlan != "" {
+		linkType = "access"
+	}
+
+	switch linkType {
+	case "access":
+		iface.VlanMode = vlanModeAccess
+		iface.VlanId = accessVlan
+	case vrpLinkTypeTrunk:
+		iface.VlanMode = trunkVlanMode(allowAll)
+		iface.VlanId = nativeVlan
+		if !allowAll {
+			iface.TaggedVlans = allowedVlans
+		}
+	}
+
+	baseName, _, isSubinterface := strings.Cut(iface.Name, ".")
+
+	switch {
+	case strings.HasPrefix(iface.Name, vrpNullName):
+		iface.Name = ""
+	case isSubinterface:
+		iface.InterfaceType = "vlan"
+		iface.Parent = baseName
+		iface.VlanMode = ""
+		iface.TaggedVlans = nil
+	case strings.HasPrefix(iface.Name, dialect.vlanInterface):
+		iface.InterfaceType = "vlan"
+		iface.VlanId = strings.TrimPrefix(iface.Name, dialect.vlanInterface)
+	case hasAnyPrefix(iface.Name, dialect.aggregatePrefixes):
+		iface.InterfaceType = "aggregate"
+	case strings.HasPrefix(iface.Name, vrpLoopbackName), strings.HasPrefix(iface.Name, vrpTunnelName):
+		iface.InterfaceType = "virtual"
+	default:
+		iface.InterfaceType = "physical"
+	}
+
+	return iface, lag
+}
+
+func hasAnyPrefix(value string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
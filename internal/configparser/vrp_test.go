@@ -0,0 +1,172 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const vrpFixture = `!Software Version V200R019C10SPC500
+#
+sysname sw1
+#
+vlan batch 10 20 to 22 30
+#
+ip vpn-instance CUST
+ ipv4-family
+#
+interface Vlanif10
+ ip binding vpn-instance CUST
+ ip address 10.0.10.1 255.255.255.0
+ ip address 10.0.11.1 255.255.255.0 sub
+#
+interface Eth-Trunk1
+ description uplink
+ port link-type trunk
+ port trunk pvid vlan 99
+ undo port trunk allow-pass vlan 1
+ port trunk allow-pass vlan 10 20 to 22
+ mode lacp
+#
+interface GigabitEthernet0/0/1
+ eth-trunk 1
+#
+interface GigabitEthernet0/0/2
+ eth-trunk 1
+#
+interface GigabitEthernet0/0/3
+ port link-type access
+ port default vlan 30
+#
+interface GigabitEthernet0/0/4
+ undo portswitch
+ ipv6 enable
+ ip address 192.0.2.2 255.255.255.252
+ ipv6 address 2001:db8::2/64
+#
+interface GigabitEthernet0/0/4.100
+ dot1q termination vid 100
+ ip address 198.51.100.1 255.255.255.0
+#
+interface GigabitEthernet0/0/5
+ shutdown
+ port link-type trunk
+ port trunk allow-pass vlan all
+#
+interface LoopBack0
+ ip address 10.255.0.1 255.255.255.255
+#
+interface NULL0
+#
+return
+`
+
+const comwareFixture = `#
+ version 7.1.070, Release 3506P06
+#
+ sysname sw2
+#
+vlan 10
+#
+interface Bridge-Aggregation1
+ description uplink
+ port link-type trunk
+ undo port trunk permit vlan 1
+ port trunk permit vlan 10 20 to 21
+ port trunk pvid vlan 99
+ link-aggregation mode dynamic
+#
+interface Route-Aggregation2
+ ip address 192.0.2.6 255.255.255.252
+#
+interface Vlan-interface10
+ ip address 10.0.10.1 255.255.255.0
+#
+interface GigabitEthernet1/0/1
+ port access vlan 10
+#
+interface GigabitEthernet1/0/2
+ shutdown
+#
+interface Ten-GigabitEthernet1/0/49
+ port link-type trunk
+ port trunk permit vlan 10 20 to 21
+ port link-aggregation group 1
+#
+interface Ten-GigabitEthernet1/0/50
+ port link-type trunk
+ port trunk permit vlan 10 20 to 21
+ port link-aggregation group 1
+#
+interface Ten-GigabitEthernet1/0/51.200
+ vlan-type dot1q vid 200
+#
+return
+`
+
+type vrpTest struct {
+	name          string
+	interfaceType string
+	status        string
+	parent        string
+	vlanMode      string
+	vlanId        string
+	taggedVlans   []string
+	members       []string
+	vrf           string
+	ipAddresses   []string
+}
+
+func checkVRPInterfaces(t *testing.T, config string, parse func(*string) (*[]model.FortigateInterface, error), tests []vrpTest, count int) {
+	t.Helper()
+	interfaces, err := parse(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		iface := findInterface(t, interfaces, tt.name)
+		if iface.InterfaceType != tt.interfaceType || iface.Status != tt.status || iface.Parent != tt.parent {
+			t.Errorf("%s: got type %q status %q parent %q, want %q %q %q", tt.name, iface.InterfaceType, iface.Status, iface.Parent, tt.interfaceType, tt.status, tt.parent)
+		}
+		if iface.VlanMode != tt.vlanMode || iface.VlanId != tt.vlanId || !slices.Equal(iface.TaggedVlans, tt.taggedVlans) {
+			t.Errorf("%s: got mode %q vlan %q tagged %v, want %q %q %v", tt.name, iface.VlanMode, iface.VlanId, iface.TaggedVlans, tt.vlanMode, tt.vlanId, tt.taggedVlans)
+		}
+		if !slices.Equal(iface.Members, tt.members) {
+			t.Errorf("%s: got members %v, want %v", tt.name, iface.Members, tt.members)
+		}
+		if iface.Vrf != tt.vrf || !slices.Equal(iface.IPAddresses, tt.ipAddresses) {
+			t.Errorf("%s: got vrf %q addresses %v, want %q %v", tt.name, iface.Vrf, iface.IPAddresses, tt.vrf, tt.ipAddresses)
+		}
+	}
+
+	if len(*interfaces) != count {
+		t.Errorf("got %d interfaces, want %d", len(*interfaces), count)
+	}
+}
+
+func TestParseVRPConfig(t *testing.T) {
+	checkVRPInterfaces(t, vrpFixture, ParseVRPConfig, []vrpTest{
+		{"Vlanif10", "vlan", "", "", "", "10", nil, nil, "CUST", []string{"10.0.10.1/24", "10.0.11.1/24"}},
+		{"Eth-Trunk1", "aggregate", "", "", vlanModeTagged, "99", []string{"10", "20", "21", "22"}, []string{"GigabitEthernet0/0/1", "GigabitEthernet0/0/2"}, "", nil},
+		{"GigabitEthernet0/0/1", "physical", "", "", "", "", nil, nil, "", nil},
+		{"GigabitEthernet0/0/3", "physical", "", "", vlanModeAccess, "30", nil, nil, "", nil},
+		{"GigabitEthernet0/0/4", "physical", "", "", "", "", nil, nil, "", []string{"192.0.2.2/30", "2001:db8::2/64"}},
+		{"GigabitEthernet0/0/4.100", "vlan", "", "GigabitEthernet0/0/4", "", "100", nil, nil, "", []string{"198.51.100.1/24"}},
+		{"GigabitEthernet0/0/5", "physical", "down", "", vlanModeTaggedAll, "", nil, nil, "", nil},
+		{"LoopBack0", "virtual", "", "", "", "", nil, nil, "", []string{"10.255.0.1/32"}},
+	}, 9)
+}
+
+func TestParseComwareConfig(t *testing.T) {
+	checkVRPInterfaces(t, comwareFixture, ParseComwareConfig, []vrpTest{
+		{"Bridge-Aggregation1", "aggregate", "", "", vlanModeTagged, "99", []string{"10", "20", "21"}, []string{"Ten-GigabitEthernet1/0/49", "Ten-GigabitEthernet1/0/50"}, "", nil},
+		{"Route-Aggregation2", "aggregate", "", "", "", "", nil, nil, "", []string{"192.0.2.6/30"}},
+		{"Vlan-interface10", "vlan", "", "", "", "10", nil, nil, "", []string{"10.0.10.1/24"}},
+		{"GigabitEthernet1/0/1", "physical", "", "", vlanModeAccess, "10", nil, nil, "", nil},
+		{"GigabitEthernet1/0/2", "physical", "down", "", "", "", nil, nil, "", nil},
+		{"Ten-GigabitEthernet1/0/49", "physical", "", "", vlanModeTagged, "", []string{"10", "20", "21"}, nil, "", nil},
+		{"Ten-GigabitEthernet1/0/51.200", "vlan", "", "Ten-GigabitEthernet1/0/51", "", "200", nil, nil, "", nil},
+	}, 8)
+}
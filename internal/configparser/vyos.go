@@ -0,0 +1,192 @@
+package configparser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+type vyosInterface struct {
+	name        string
+	kind        string
+	description string
+	disabled    bool
+	parent      string
+	vlanId      string
+	addresses   []string
+	members     []string
+	bondGroup   string
+	bridgeGroup string
+	hashPolicy  string
+	bondMode    string
+	vrf         string
+}
+
+// ParseVyOSConfig parses VyOS and Ubiquiti EdgeOS configs, both the curly-brace
+// and the "set" form are supported.
+func ParseVyOSConfig(config *string) (*[]model.FortigateInterface, error) {
+	var deviceInterfaces []model.FortigateInterface
+
+	vyosInterfaces := parseVyOSInterfaces(statementsWithPrefix(parseHierarchicalConfig(config), "interfaces"))
+
+	lagMembers := map[string][]string{}
+	var bridges []model.FortigateVirtualSwitch
+	bridgeIndex := map[string]int{}
+
+	for _, vyosIface := range vyosInterfaces {
+		if vyosIface.isBridge() {
+			bridgeIndex[vyosIface.name] = len(bridges)
+			bridges = append(bridges, model.FortigateVirtualSwitch{Name: vyosIface.name, Members: vyosIface.members})
+		}
+	}
+
+	for _, vyosIface := range vyosInterfaces {
+		if vyosIface.bondGroup != "" {
+			lagMembers[vyosIface.bondGroup] = append(lagMembers[vyosIface.bondGroup], vyosIface.name)
+		}
+		if index, ok := bridgeIndex[vyosIface.bridgeGroup]; ok {
+			bridges[index].Members = append(bridges[index].Members, vyosIface.name)
+		}
+		if vyosIface.isBridge() {
+			continue
+		}
+
+		var iface model.FortigateInterface
+		iface.Name = vyosIface.name
+		iface.Description = vyosIface.description
+		iface.IPAddresses = vyosIface.addresses
+		iface.Vrf = vyosIface.vrf
+		if vyosIface.disabled {
+			iface.Status = "down"
+		}
+
+		switch {
+		case vyosIface.parent != "":
+			iface.InterfaceType = "vlan"
+			iface.Parent = vyosIface.parent
+			iface.VlanId = vyosIface.vlanId
+		case vyosIface.kind == "ethernet":
+			iface.InterfaceType = "physical"
+		case vyosIface.kind == "bonding":
+			iface.InterfaceType = "aggregate"
+			iface.Members = vyosIface.members
+			if vyosIface.bondMode != "" {
+				createDescriptionBuilder(vyosIface.bondMode, "mode", &iface.Description)
+			}
+			if vyosIface.hashPolicy != "" {
+				createDescriptionBuilder(vyosIface.hashPolicy, "hash-policy", &iface.Description)
+			}
+		default:
+			iface.InterfaceType = "virtual"
+		}
+
+		deviceInterfaces = append(deviceInterfaces, iface)
+	}
+
+	addLagMembers(&deviceInterfaces, lagMembers)
+	convertVirtualSwitch(&bridges, &deviceInterfaces)
+
+	// the bridge itself can have an address, description or vifs
+	for _, vyosIface := range vyosInterfaces {
+		if !vyosIface.isBridge() {
+			continue
+		}
+		for index, iface := range deviceInterfaces {
+			if iface.Name != vyosIface.name {
+				continue
+			}
+			if vyosIface.description != "" {
+				deviceInterfaces[index].Description = vyosIface.description
+			}
+			deviceInterfaces[index].IPAddresses = vyosIface.addresses
+			deviceInterfaces[index].Vrf = vyosIface.vrf
+			if vyosIface.disabled {
+				deviceInterfaces[index].Status = "down"
+			}
+		}
+	}
+
+	return &deviceInterfaces, nil
+}
+
+func parseVyOSInterfaces(statements [][]string) []*vyosInterface {
+	var result []*vyosInterface
+	byName := map[string]*vyosInterface{}
+
+	getInterface := func(kind string, name string) *vyosInterface {
+		iface, ok := byName[name]
+		if !ok {
+			iface = &vyosInterface{name: name, kind: kind}
+			byName[name] = iface
+			result = append(result, iface)
+		}
+		return iface
+	}
+
+	for _, statement := range statements {
+		if len(statement) < 2 {
+			continue
+		}
+
+		iface := getInterface(statement[0], statement[1])
+		attributes := statement[2:]
+
+		// vif 10 { ... }, vif-s 100 { vif-c 10 { ... } }
+		for len(attributes) >= 2 && (attributes[0] == "vif" || attributes[0] == "vif-s" || attributes[0] == "vif-c") {
+			child := getInterface(iface.kind, iface.name+"."+attributes[1])
+			child.parent = iface.name
+			child.vlanId = attributes[1]
+			iface = child
+			attributes = attributes[2:]
+		}
+
+		if len(attributes) == 0 {
+			continue
+		}
+
+		switch attributes[0] {
+		case "description":
+			iface.description = strings.Join(attributes[1:], " ")
+		case "disable":
+			iface.disabled = true
+		case "address":
+			if len(attributes) > 1 && strings.Contains(attributes[1], "/") {
+				iface.addresses = append(iface.addresses, attributes[1])
+			}
+		case "vrf":
+			if len(attributes) > 1 {
+				iface.vrf = attributes[1]
+			}
+		case "hash-policy":
+			if len(attributes) > 1 {
+				iface.hashPolicy = attributes[1]
+			}
+		case "mode":
+			if len(attributes) > 1 {
+				iface.bondMode = attributes[1]
+			}
+		case "bond-group":
+			if len(attributes) > 1 {
+				iface.bondGroup = attributes[1]
+			}
+		case "bridge-group":
+			// EdgeOS: bridge-group { bridge br0 }
+			if len(attributes) > 2 && attributes[1] == "bridge" {
+				iface.bridgeGroup = attributes[2]
+			}
+		case "member", "switch-port":
+			// VyOS: member { interface eth1 }, EdgeOS: switch-port { interface eth1 }
+			if len(attributes) > 2 && attributes[1] == "interface" && !slices.Contains(iface.members, attributes[2]) {
+				iface.members = append(iface.members, attributes[2])
+			}
+		}
+	}
+
+	return result
+}
+
+// isBridge reports if the interface is a bridge or EdgeOS switch, their vifs are normal vlans.
+func (iface *vyosInterface) isBridge() bool {
+	return (iface.kind == "bridge" || iface.kind == "switch") && iface.parent == ""
+}
@@ -0,0 +1,174 @@
+package configparser
+
+import (
+	"slices"
+	"testing"
+)
+
+const vyosBraceFixture = `interfaces {
+    bonding bond0 {
+        description "to core"
+        hash-policy layer3+4
+        member {
+            interface eth1
+            interface eth2
+        }
+        mode 802.3ad
+        vif 100 {
+            address 10.0.100.1/24
+            description servers
+        }
+    }
+    bridge br0 {
+        address 10.0.50.1/24
+        member {
+            interface eth3 {
+            }
+        }
+    }
+    ethernet eth0 {
+        address 192.0.2.2/24
+        address dhcpv6
+        description WAN
+        hw-id 00:53:00:00:00:01
+        vrf RED
+    }
+    ethernet eth1 {
+        hw-id 00:53:00:00:00:02
+    }
+    ethernet eth2 {
+        hw-id 00:53:00:00:00:03
+    }
+    ethernet eth3 {
+        hw-id 00:53:00:00:00:04
+    }
+    ethernet eth4 {
+        disable
+        vif-s 200 {
+            vif-c 10 {
+                address 10.2.10.1/24
+            }
+        }
+    }
+    loopback lo {
+    }
+}
+system {
+    host-name vyos
+}
+// Warning: Do not remove the following line.
+// vyos-config-version: "bgp@4:broadcast-relay@1:cluster@1"
+`
+
+const vyosSetFixture = `set interfaces bonding bond0 description 'to core'
+set interfaces bonding bond0 hash-policy 'layer3+4'
+set interfaces bonding bond0 member interface 'eth1'
+set interfaces bonding bond0 member interface 'eth2'
+set interfaces bonding bond0 mode '802.3ad'
+set interfaces bonding bond0 vif 100 address '10.0.100.1/24'
+set interfaces bonding bond0 vif 100 description 'servers'
+set interfaces bridge br0 address '10.0.50.1/24'
+set interfaces bridge br0 member interface eth3
+set interfaces ethernet eth0 address '192.0.2.2/24'
+set interfaces ethernet eth0 address 'dhcpv6'
+set interfaces ethernet eth0 description 'WAN'
+set interfaces ethernet eth0 hw-id '00:53:00:00:00:01'
+set interfaces ethernet eth0 vrf 'RED'
+set interfaces ethernet eth1 hw-id '00:53:00:00:00:02'
+set interfaces ethernet eth2 hw-id '00:53:00:00:00:03'
+set interfaces ethernet eth3 hw-id '00:53:00:00:00:04'
+set interfaces ethernet eth4 disable
+set interfaces ethernet eth4 vif-s 200 vif-c 10 address '10.2.10.1/24'
+set interfaces loopback lo
+set system host-name 'vyos'
+`
+
+func TestParseVyOSConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		interfaceType string
+		status        string
+		description   string
+		parent        string
+		vlanId        string
+		members       []string
+		vrf           string
+		ipAddresses   []string
+	}{
+		{"bond0", "aggregate", "", "to core; mode: 802.3ad; hash-policy: layer3+4", "", "", []string{"eth1", "eth2"}, "", nil},
+		{"bond0.100", "vlan", "", "servers", "bond0", "100", nil, "", []string{"10.0.100.1/24"}},
+		{"br0", "virtual-switch", "", "virtual-switch", "", "", []string{"eth3"}, "", []string{"10.0.50.1/24"}},
+		{"eth0", "physical", "", "WAN", "", "", nil, "RED", []string{"192.0.2.2/24"}},
+		{"eth1", "physical", "", "", "", "", nil, "", nil},
+		{"eth3", "physical", "", "", "br0", "", nil, "", nil},
+		{"eth4", "physical", "down", "", "", "", nil, "", nil},
+		{"eth4.200", "vlan", "", "", "eth4", "200", nil, "", nil},
+		{"eth4.200.10", "vlan", "", "", "eth4.200", "10", nil, "", []string{"10.2.10.1/24"}},
+		{"lo", "virtual", "", "", "", "", nil, "", nil},
+	}
+
+	for format, fixture := range map[string]string{"brace": vyosBraceFixture, "set": vyosSetFixture} {
+		config := fixture
+		interfaces, err := ParseVyOSConfig(&config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, tt := range tests {
+			iface := findInterface(t, interfaces, tt.name)
+			if iface.InterfaceType != tt.interfaceType || iface.Status != tt.status || iface.Description != tt.description || iface.Parent != tt.parent {
+				t.Errorf("%s %s: got type %q status %q description %q parent %q, want %q %q %q %q", format, tt.name,
+					iface.InterfaceType, iface.Status, iface.Description, iface.Parent, tt.interfaceType, tt.status, tt.description, tt.parent)
+			}
+			if iface.VlanId != tt.vlanId || !slices.Equal(iface.Members, tt.members) {
+				t.Errorf("%s %s: got vlan %q members %v, want %q %v", format, tt.name, iface.VlanId, iface.Members, tt.vlanId, tt.members)
+			}
+			if iface.Vrf != tt.vrf || !slices.Equal(iface.IPAddresses, tt.ipAddresses) {
+				t.Errorf("%s %s: got vrf %q addresses %v, want %q %v", format, tt.name, iface.Vrf, iface.IPAddresses, tt.vrf, tt.ipAddresses)
+			}
+		}
+
+		if len(*interfaces) != 11 {
+			t.Errorf("%s: got %d interfaces, want 11", format, len(*interfaces))
+		}
+	}
+}
+
+func TestParseVyOSConfigEdgeOS(t *testing.T) {
+	config := `interfaces {
+    bridge br0 {
+        address 10.0.0.1/24
+    }
+    ethernet eth1 {
+        bridge-group {
+            bridge br0
+        }
+    }
+    switch switch0 {
+        address 192.168.1.1/24
+        switch-port {
+            interface eth2 {
+            }
+            interface eth3 {
+            }
+        }
+        vif 10 {
+            address 192.168.10.1/24
+        }
+    }
+}
+`
+	interfaces, err := ParseVyOSConfig(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, members := range map[string][]string{"br0": {"eth1"}, "switch0": {"eth2", "eth3"}} {
+		if iface := findInterface(t, interfaces, name); iface.InterfaceType != "virtual-switch" || !slices.Equal(iface.Members, members) {
+			t.Errorf("%s: got type %q members %v, want virtual-switch %v", name, iface.InterfaceType, iface.Members, members)
+		}
+	}
+	if vif := findInterface(t, interfaces, "switch0.10"); vif.InterfaceType != "vlan" || vif.Parent != "switch0" || vif.VlanId != "10" {
+		t.Errorf("switch0.10: got type %q parent %q vlan %q", vif.InterfaceType, vif.Parent, vif.VlanId)
+	}
+}
@@ -57,6 +57,17 @@ func TokenAuthHTTPPatch(fullurl string, token string, client *http.Client, jsonb
 	return httpDo(req, client)
 }
 
+func TokenAuthHTTPDelete(fullurl string, token string, client *http.Client) error {
+	req, err := http.NewRequest(http.MethodDelete, fullurl, nil)
+	if err != nil {
+		return fmt.Errorf("could not create request: %s", err)
+	}
+	req.Header.Add("Authorization", "Token "+ token)
+
+	_, err = httpDo(req, client)
+	return err
+}
+
 func httpDo(req *http.Request, client *http.Client) ([]byte, error) {
 	res, err := client.Do(req)
 	
@@ -77,6 +88,9 @@ func httpDo(req *http.Request, client *http.Client) ([]byte, error) {
 		if req.Method == http.MethodPost && res.StatusCode == http.StatusCreated{
 			return resBody, nil
 		}
+		if req.Method == http.MethodDelete && res.StatusCode == http.StatusNoContent {
+			return resBody, nil
+		}
 	}	
 	return nil, fmt.Errorf("http status was not 200")
 
@@ -6,12 +6,18 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
 )
 
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
 type netboxResult struct {
 	Count    int             `json:"count"`
 	Next     string          `json:"next"`
@@ -20,29 +26,45 @@ type netboxResult struct {
 }
 
 type interfacePatchData struct {
-	Description   string   `json:"description,omitempty"`
-	Enabled       *bool    `json:"enabled,omitempty"`
-	Parent        int      `json:"parent,omitempty"`
-	Lag           int      `json:"lag,omitempty"`
-	Bridge        int      `json:"bridge,omitempty"`
-	InterfaceType string   `json:"type,omitempty"`
-	Mode          string   `json:"mode,omitempty"`
-	UntaggedVlan  int      `json:"untagged_vlan,omitempty"`
-	Tags          []string `json:"tags,omitempty"`
+	Description   string                 `json:"description,omitempty"`
+	Enabled       *bool                  `json:"enabled,omitempty"`
+	Parent        int                    `json:"parent,omitempty"`
+	Lag           int                    `json:"lag,omitempty"`
+	Bridge        int                    `json:"bridge,omitempty"`
+	InterfaceType string                 `json:"type,omitempty"`
+	Mode          string                 `json:"mode,omitempty"`
+	UntaggedVlan  int                    `json:"untagged_vlan,omitempty"`
+	TaggedVlans   *[]int                 `json:"tagged_vlans,omitempty"`
+	Vrf           int                    `json:"vrf,omitempty"`
+	Label         string                 `json:"label,omitempty"`
+	MgmtOnly      *bool                  `json:"mgmt_only,omitempty"`
+	Vdcs          *[]int                 `json:"vdcs,omitempty"`
+	Speed         int                    `json:"speed,omitempty"`
+	Duplex        string                 `json:"duplex,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	CustomFields  map[string]interface{} `json:"custom_fields,omitempty"`
 }
 
 type interfacePostData struct {
-	Device        int      `json:"device"`
-	Name          string   `json:"name"`
-	InterfaceType string   `json:"type"`
-	Description   string   `json:"description,omitempty"`
-	Enabled       *bool    `json:"enabled,omitempty"`
-	UntaggedVlan  int      `json:"untagged_vlan,omitempty"`
-	Mode          string   `json:"mode,omitempty"`
-	Parent        int      `json:"parent,omitempty"`
-	Bridge        int      `json:"bridge,omitempty"`
-	Lag           int      `json:"lag,omitempty"`
-	Tags          []string `json:"tags,omitempty"`
+	Device        int                    `json:"device"`
+	Name          string                 `json:"name"`
+	InterfaceType string                 `json:"type"`
+	Description   string                 `json:"description,omitempty"`
+	Enabled       *bool                  `json:"enabled,omitempty"`
+	UntaggedVlan  int                    `json:"untagged_vlan,omitempty"`
+	TaggedVlans   []int                  `json:"tagged_vlans,omitempty"`
+	Vrf           int                    `json:"vrf,omitempty"`
+	Label         string                 `json:"label,omitempty"`
+	MgmtOnly      *bool                  `json:"mgmt_only,omitempty"`
+	Vdcs          []int                  `json:"vdcs,omitempty"`
+	Mode          string                 `json:"mode,omitempty"`
+	Parent        int                    `json:"parent,omitempty"`
+	Bridge        int                    `json:"bridge,omitempty"`
+	Lag           int                    `json:"lag,omitempty"`
+	Speed         int                    `json:"speed,omitempty"`
+	Duplex        string                 `json:"duplex,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	CustomFields  map[string]interface{} `json:"custom_fields,omitempty"`
 }
 
 type vlanPostData struct {
@@ -53,6 +75,44 @@ type vlanPostData struct {
 	Tags     []string `json:"tags,omitempty"`
 }
 
+type vrfPostData struct {
+	Name     string   `json:"name"`
+	TenantId int      `json:"tenant,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+type vdcPostData struct {
+	Name     string   `json:"name"`
+	Device   int      `json:"device"`
+	Status   string   `json:"status"`
+	TenantId int      `json:"tenant,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+type l2vpnPostData struct {
+	Name       string   `json:"name"`
+	Slug       string   `json:"slug"`
+	Type       string   `json:"type"`
+	Identifier int      `json:"identifier"`
+	TenantId   int      `json:"tenant,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+type l2vpnTerminationPostData struct {
+	L2vpn              int      `json:"l2vpn"`
+	AssignedObjectType string   `json:"assigned_object_type"`
+	AssignedObjectId   int      `json:"assigned_object_id"`
+	Tags               []string `json:"tags,omitempty"`
+}
+
+type customFieldPostData struct {
+	Name        string   `json:"name"`
+	Label       string   `json:"label"`
+	Type        string   `json:"type"`
+	ObjectTypes []string `json:"object_types"`
+	Description string   `json:"description,omitempty"`
+}
+
 type tagPostData struct {
 	Name        string `json:"name"`
 	Slug        string `json:"slug"`
@@ -61,7 +121,7 @@ type tagPostData struct {
 }
 
 type netboxData interface {
-	model.NetboxInterface | model.NetboxDevice | model.NetboxVlan | model.NetboxTag
+	model.NetboxInterface | model.NetboxDevice | model.NetboxVlan | model.NetboxTag | model.NetboxVrf | model.NetboxTenant | model.NetboxVdc | model.NetboxL2vpn | model.NetboxIPAddress | model.NetboxPrefix | model.NetboxFhrpGroup | model.NetboxFhrpGroupAssignment | model.NetboxTunnel | model.NetboxTunnelTermination | model.NetboxCustomField
 }
 
 type NetboxHTTPClient struct {
@@ -70,6 +130,12 @@ type NetboxHTTPClient struct {
 	client      http.Client
 	rolesfilter string
 	defaultTag  model.NetboxTag
+	cachedTags  map[string]model.NetboxTag
+	tagLock     *sync.Mutex
+	createLock  *sync.Mutex
+	vrfFormat   string
+	vrfTenantId int
+	fieldsOnce  *sync.Once
 }
 
 func NewNetbox(baseurl string, apikey string, roles string) NetboxHTTPClient {
@@ -91,17 +157,17 @@ func NewNetbox(baseurl string, apikey string, roles string) NetboxHTTPClient {
 		rolesfilter = sb.String()
 	}
 
-	e := NetboxHTTPClient{apikey, baseurl, *client, rolesfilter, model.NetboxTag{}}
+	e := NetboxHTTPClient{apikey, baseurl, *client, rolesfilter, model.NetboxTag{}, map[string]model.NetboxTag{}, &sync.Mutex{}, &sync.Mutex{}, "", 0, &sync.Once{}}
 	return e
 }
 
 func (e *NetboxHTTPClient) GetManagedTag(tagName string) {
 	tag, err := getNetboxTagByName(tagName, e)
 	if err != nil {
-		slog.Error("Error getting tags", err)
+		slog.Error("Error getting tags", "error", err)
 	}
 	if tag.ID == 0 {
-		newTag := e.createNetboxTag(tagName)
+		newTag := e.createNetboxTag(tagName, "Auto generated tag to track objects created by the oxidized sync", "72599f")
 		e.defaultTag = newTag
 	} else {
 		e.defaultTag = tag
@@ -126,17 +192,17 @@ func slugify(input string) string {
 	var result string
 
 	result = strings.ToLower(input)
-	result = strings.Replace(result, " ", "-", -1)
+	result = slugInvalidChars.ReplaceAllString(result, "-")
 
 	return result
 }
 
-func (e *NetboxHTTPClient) createNetboxTag(tagName string) model.NetboxTag {
+func (e *NetboxHTTPClient) createNetboxTag(tagName string, description string, color string) model.NetboxTag {
 	var postData tagPostData
 	postData.Name = tagName
 	postData.Slug = slugify(tagName)
-	postData.Description = "Auto generated tag to track objects created by the oxidized sync"
-	postData.Color = "72599f"
+	postData.Description = description
+	postData.Color = color
 
 	data, _ := json.Marshal(postData)
 	requestURL := fmt.Sprintf("%s/api/extras/tags/", e.baseurl)
@@ -153,6 +219,105 @@ func (e *NetboxHTTPClient) createNetboxTag(tagName string) model.NetboxTag {
 	return result
 }
 
+func (e *NetboxHTTPClient) getOrCreateZoneTag(zone string) int {
+	return e.getOrCreateCachedTag(model.ZoneTagPrefix+zone, fmt.Sprintf("Firewall zone %s", zone), "ff9800")
+}
+
+func (e *NetboxHTTPClient) getOrCreateSdwanTag(zone string) int {
+	return e.getOrCreateCachedTag(model.SdwanTagPrefix+zone, fmt.Sprintf("SD-WAN zone %s", zone), "2196f3")
+}
+
+// getOrCreateCachedTag returns the id of the tag, the tags are cached as the same
+// zone tags are used on many interfaces. The lock is held until the tag is created
+// as the workers share the client.
+func (e *NetboxHTTPClient) getOrCreateCachedTag(tagName string, description string, color string) int {
+	e.tagLock.Lock()
+	defer e.tagLock.Unlock()
+
+	if tag, ok := e.cachedTags[tagName]; ok {
+		return tag.ID
+	}
+
+	tag, err := getNetboxTagByName(tagName, e)
+	if err != nil {
+		slog.Error("Error getting tags", "error", err)
+		return 0
+	}
+	if tag.ID == 0 {
+		tag = e.createNetboxTag(tagName, description, color)
+	}
+	if tag.ID != 0 {
+		e.cachedTags[tagName] = tag
+	}
+	return tag.ID
+}
+
+// ensureSdwanCustomFields creates the interface custom fields of the SD-WAN gateway and
+// priority when they do not exist yet, this is only done once for all workers.
+func (e *NetboxHTTPClient) ensureSdwanCustomFields() {
+	e.fieldsOnce.Do(func() {
+		requestURL := fmt.Sprintf("%s/api/extras/custom-fields/", e.baseurl)
+		customFields, err := apiRequest[model.NetboxCustomField](requestURL, e)
+		if err != nil {
+			slog.Error("Error getting custom fields", "error", err)
+			return
+		}
+		e.createCustomField(&customFields, model.SdwanGatewayField, "SD-WAN gateway", "text")
+		e.createCustomField(&customFields, model.SdwanPriorityField, "SD-WAN priority", "integer")
+	})
+}
+
+func (e *NetboxHTTPClient) createCustomField(customFields *[]model.NetboxCustomField, name string, label string, fieldType string) {
+	for _, customField := range *customFields {
+		if customField.Name == name {
+			return
+		}
+	}
+
+	var postData customFieldPostData
+	postData.Name = name
+	postData.Label = label
+	postData.Type = fieldType
+	postData.ObjectTypes = []string{interfaceObjectType}
+	postData.Description = "Auto generated by the oxidized sync"
+
+	data, _ := json.Marshal(postData)
+	requestURL := fmt.Sprintf("%s/api/extras/custom-fields/", e.baseurl)
+	_, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not create custom field", "field", name, "error", err)
+	}
+}
+
+// sdwanCustomFields returns the changed SD-WAN custom fields of the interface, an empty
+// value clears the field.
+func (e *NetboxHTTPClient) sdwanCustomFields(port model.NetboxInterfaceUpdateCreate) map[string]interface{} {
+	if port.SdwanGateway == nil && port.SdwanPriority == nil {
+		return nil
+	}
+
+	customFields := map[string]interface{}{}
+	if port.SdwanGateway != nil {
+		customFields[model.SdwanGatewayField] = nil
+		if *port.SdwanGateway != "" {
+			customFields[model.SdwanGatewayField] = *port.SdwanGateway
+		}
+	}
+	if port.SdwanPriority != nil {
+		customFields[model.SdwanPriorityField] = nil
+		if priority, err := strconv.Atoi(*port.SdwanPriority); err == nil {
+			customFields[model.SdwanPriorityField] = priority
+		}
+	}
+	for _, value := range customFields {
+		if value != nil {
+			e.ensureSdwanCustomFields()
+			break
+		}
+	}
+	return customFields
+}
+
 func loopAPIRequest(path string, e *NetboxHTTPClient) (netboxResult, error) {
 	resBody, err := TokenAuthHTTPGet(path, e.apikey, &e.client)
 	if err != nil {
@@ -219,6 +384,151 @@ func (e *NetboxHTTPClient) GetVlansForSite(siteId string) ([]model.NetboxVlan, e
 	return vlans, nil
 }
 
+func (e *NetboxHTTPClient) GetAllVrfs() ([]model.NetboxVrf, error) {
+	requestURL := fmt.Sprintf("%s/api/ipam/vrfs/", e.baseurl)
+	vrfs, err := apiRequest[model.NetboxVrf](requestURL, e)
+	if err != nil {
+		return []model.NetboxVrf{}, err
+	}
+	return vrfs, nil
+}
+
+// SetVrfOptions sets the naming scheme and tenant of the vrfs created by the sync.
+// The name format can use {vrf}, {device} and {site}, an empty tenant keeps the
+// tenant of the device.
+func (e *NetboxHTTPClient) SetVrfOptions(nameFormat string, tenantSlug string) {
+	e.vrfFormat = nameFormat
+	if tenantSlug == "" {
+		return
+	}
+
+	requestURL := fmt.Sprintf("%s/api/tenancy/tenants/?slug=%s", e.baseurl, tenantSlug)
+	tenants, err := apiRequest[model.NetboxTenant](requestURL, e)
+	if err != nil {
+		slog.Error("Error getting vrf tenant", "tenant", tenantSlug, "error", err)
+		return
+	}
+	if len(tenants) == 0 {
+		slog.Warn("Vrf tenant not found, using the tenant of the device", "tenant", tenantSlug)
+		return
+	}
+	e.vrfTenantId = tenants[0].ID
+}
+
+// VrfName returns the NetBox name of a vrf found in the config of the device.
+func (e *NetboxHTTPClient) VrfName(vrf string, netboxDevice model.NetboxDevice) string {
+	if vrf == "" || e.vrfFormat == "" {
+		return vrf
+	}
+	replacer := strings.NewReplacer("{vrf}", vrf, "{device}", netboxDevice.Name, "{site}", netboxDevice.Site.Name)
+	return replacer.Replace(e.vrfFormat)
+}
+
+func getNetboxVrfInternalID(vrfs *[]model.NetboxVrf, name string) int {
+	for _, vrf := range *vrfs {
+		if strings.EqualFold(vrf.Name, name) {
+			return vrf.ID
+		}
+	}
+	return 0
+}
+
+func (e *NetboxHTTPClient) createVrf(name string, tenantId int) model.NetboxVrf {
+	var postData vrfPostData
+	postData.Name = name
+	postData.TenantId = tenantId
+	postData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
+
+	data, _ := json.Marshal(postData)
+	requestURL := fmt.Sprintf("%s/api/ipam/vrfs/", e.baseurl)
+	resBody, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+
+	var result model.NetboxVrf
+	err = json.Unmarshal(resBody, &result)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+	return result
+}
+
+// getOrCreateVrf returns the id of the vrf, a vrf that is not in the list is looked up
+// again under the create lock as another worker can have created it in the meantime.
+func (e *NetboxHTTPClient) getOrCreateVrf(netboxVrfs *[]model.NetboxVrf, name string, netboxTenantId int) int {
+	netboxVrfId := getNetboxVrfInternalID(netboxVrfs, name)
+	if netboxVrfId != 0 {
+		return netboxVrfId
+	}
+
+	if e.vrfTenantId != 0 {
+		netboxTenantId = e.vrfTenantId
+	}
+
+	e.createLock.Lock()
+	defer e.createLock.Unlock()
+
+	requestURL := fmt.Sprintf("%s/api/ipam/vrfs/?name__ie=%s", e.baseurl, url.QueryEscape(name))
+	vrfs, err := apiRequest[model.NetboxVrf](requestURL, e)
+	if err != nil {
+		slog.Error("Error getting vrf", "vrf", name, "error", err)
+		return 0
+	}
+	var vrf model.NetboxVrf
+	if len(vrfs) > 0 {
+		vrf = vrfs[0]
+	} else {
+		vrf = e.createVrf(name, netboxTenantId)
+	}
+	*netboxVrfs = append(*netboxVrfs, vrf)
+	return vrf.ID
+}
+
+func (e *NetboxHTTPClient) GetVdcsForDevice(deviceId string) ([]model.NetboxVdc, error) {
+	requestURL := fmt.Sprintf("%s/api/dcim/virtual-device-contexts/?device_id=%s", e.baseurl, deviceId)
+	vdcs, err := apiRequest[model.NetboxVdc](requestURL, e)
+	if err != nil {
+		return []model.NetboxVdc{}, err
+	}
+	return vdcs, nil
+}
+
+func (e *NetboxHTTPClient) createVdc(name string, deviceId int, tenantId int) model.NetboxVdc {
+	var postData vdcPostData
+	postData.Name = name
+	postData.Device = deviceId
+	postData.Status = "active"
+	postData.TenantId = tenantId
+	postData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
+
+	data, _ := json.Marshal(postData)
+	requestURL := fmt.Sprintf("%s/api/dcim/virtual-device-contexts/", e.baseurl)
+	resBody, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+
+	var result model.NetboxVdc
+	err = json.Unmarshal(resBody, &result)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+	return result
+}
+
+func (e *NetboxHTTPClient) getOrCreateVdc(netboxVdcs *[]model.NetboxVdc, name string, deviceId int, netboxTenantId int) int {
+	for _, vdc := range *netboxVdcs {
+		if strings.EqualFold(vdc.Name, name) {
+			return vdc.ID
+		}
+	}
+
+	vdc := e.createVdc(name, deviceId, netboxTenantId)
+	*netboxVdcs = append(*netboxVdcs, vdc)
+	return vdc.ID
+}
+
 func getNetboxVlanInternalID(vlans *[]model.NetboxVlan, vid int) int {
 	for _, vlan := range *vlans {
 		if vlan.Vid == vid {
@@ -252,7 +562,45 @@ func (e *NetboxHTTPClient) createVlan(SiteId int, TenantId int, VlanId int, Name
 
 }
 
-func (e *NetboxHTTPClient) updateInterface(port model.NetboxInterfaceUpdateCreate, netboxVlansForSite *[]model.NetboxVlan, netboxSiteId int, netboxTenantId int) {
+func defaultVlanName(vid int) string {
+	return fmt.Sprintf("VLAN%d", vid)
+}
+
+// getVlanName names a new vlan after the vlan interface, switch ports fall back to a generic name.
+func getVlanName(port model.NetboxInterfaceUpdateCreate, vid int) string {
+	if port.PortType == "vlan" {
+		return port.Name
+	}
+	return defaultVlanName(vid)
+}
+
+func (e *NetboxHTTPClient) getOrCreateVlan(netboxVlansForSite *[]model.NetboxVlan, netboxSiteId int, netboxTenantId int, vid int, name string) int {
+	netboxVlanId := getNetboxVlanInternalID(netboxVlansForSite, vid)
+	if netboxVlanId != 0 {
+		return netboxVlanId
+	}
+
+	vlan := e.createVlan(netboxSiteId, netboxTenantId, vid, name)
+	*netboxVlansForSite = append(*netboxVlansForSite, vlan)
+	return vlan.ID
+}
+
+func (e *NetboxHTTPClient) getTaggedVlanIds(vlanIds []string, netboxVlansForSite *[]model.NetboxVlan, netboxSiteId int, netboxTenantId int) []int {
+	result := []int{}
+	for _, vlanId := range vlanIds {
+		vid, err := strconv.Atoi(vlanId)
+		if err != nil {
+			continue
+		}
+		netboxVlanId := e.getOrCreateVlan(netboxVlansForSite, netboxSiteId, netboxTenantId, vid, defaultVlanName(vid))
+		if netboxVlanId != 0 {
+			result = append(result, netboxVlanId)
+		}
+	}
+	return result
+}
+
+func (e *NetboxHTTPClient) updateInterface(port model.NetboxInterfaceUpdateCreate, netboxVlansForSite *[]model.NetboxVlan, netboxVrfs *[]model.NetboxVrf, netboxVdcs *[]model.NetboxVdc, netboxSiteId int, netboxTenantId int) {
 	t := new(bool)
 	f := new(bool)
 
@@ -298,30 +646,38 @@ func (e *NetboxHTTPClient) updateInterface(port model.NetboxInterfaceUpdateCreat
 		}
 	}
 
-	if port.PortTypeUpdate != "" {
-		if port.PortTypeUpdate == "virtual" {
-			patchData.InterfaceType = "virtual"
-		}
-		if port.PortTypeUpdate == "bridge" {
-			patchData.InterfaceType = "bridge"
-		}
-
+	if port.PortTypeUpdate != "" && port.PortTypeUpdate != "lag" {
+		patchData.InterfaceType = port.PortTypeUpdate
 	}
+	patchData.Speed = port.Speed
+	patchData.Duplex = port.Duplex
 	if port.VlanMode != "" {
 		patchData.Mode = port.VlanMode
 	}
 
 	if port.VlanId != "" {
 		vid, _ := strconv.Atoi(port.VlanId)
-		netboxVlanId := getNetboxVlanInternalID(netboxVlansForSite, vid)
-		if netboxVlanId != 0 {
-			patchData.UntaggedVlan = netboxVlanId
-		} else {
-			vlan := e.createVlan(netboxSiteId, netboxTenantId, vid, port.Name)
-			*netboxVlansForSite = append(*netboxVlansForSite, vlan)
-		}
+		patchData.UntaggedVlan = e.getOrCreateVlan(netboxVlansForSite, netboxSiteId, netboxTenantId, vid, getVlanName(port, vid))
 	}
 
+	if port.TaggedVlans != nil {
+		taggedVlans := e.getTaggedVlanIds(port.TaggedVlans, netboxVlansForSite, netboxSiteId, netboxTenantId)
+		patchData.TaggedVlans = &taggedVlans
+	}
+
+	if port.Vrf != "" {
+		patchData.Vrf = e.getOrCreateVrf(netboxVrfs, port.Vrf, netboxTenantId)
+	}
+
+	if port.Vdc != "" {
+		deviceId, _ := strconv.Atoi(port.DeviceId)
+		vdcs := []int{e.getOrCreateVdc(netboxVdcs, port.Vdc, deviceId, netboxTenantId)}
+		patchData.Vdcs = &vdcs
+	}
+
+	patchData.Label = port.Label
+	patchData.MgmtOnly = port.MgmtOnly
+
 	if len(port.Tags) != 0 {
 		for _,tag := range port.Tags {
 			if tag != strconv.Itoa(e.defaultTag.ID) {
@@ -333,6 +689,19 @@ func (e *NetboxHTTPClient) updateInterface(port model.NetboxInterfaceUpdateCreat
 		patchData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
 	}
 
+	if port.Zone != nil && *port.Zone != "" {
+		if zoneTag := e.getOrCreateZoneTag(*port.Zone); zoneTag != 0 {
+			patchData.Tags = append(patchData.Tags, strconv.Itoa(zoneTag))
+		}
+	}
+	if port.SdwanZone != nil && *port.SdwanZone != "" {
+		if sdwanTag := e.getOrCreateSdwanTag(*port.SdwanZone); sdwanTag != 0 {
+			patchData.Tags = append(patchData.Tags, strconv.Itoa(sdwanTag))
+		}
+	}
+
+	patchData.CustomFields = e.sdwanCustomFields(port)
+
 	data, _ := json.Marshal(patchData)
 	requestURL := fmt.Sprintf("%s/%s%s/", e.baseurl, "api/dcim/interfaces/", port.InterfaceId)
 	_, err := TokenAuthHTTPPatch(requestURL, e.apikey, &e.client, data)
@@ -342,7 +711,7 @@ func (e *NetboxHTTPClient) updateInterface(port model.NetboxInterfaceUpdateCreat
 
 }
 
-func (e *NetboxHTTPClient) createInterface(port model.NetboxInterfaceUpdateCreate, netboxVlansForSite *[]model.NetboxVlan, netboxSiteId int, netboxTenantId int) {
+func (e *NetboxHTTPClient) createInterface(port model.NetboxInterfaceUpdateCreate, netboxVlansForSite *[]model.NetboxVlan, netboxVrfs *[]model.NetboxVrf, netboxVdcs *[]model.NetboxVdc, netboxSiteId int, netboxTenantId int) {
 	t := new(bool)
 	f := new(bool)
 
@@ -359,26 +728,39 @@ func (e *NetboxHTTPClient) createInterface(port model.NetboxInterfaceUpdateCreat
 		postData.InterfaceType = "virtual"
 	} else if port.PortType == "virtual-switch" {
 		postData.InterfaceType = "bridge"
+	} else if port.PortType == "virtual" {
+		postData.InterfaceType = "virtual"
 	}
 
 	if port.VlanId != "" {
 		vid, _ := strconv.Atoi(port.VlanId)
-		netboxVlanId := getNetboxVlanInternalID(netboxVlansForSite, vid)
-		if netboxVlanId != 0 {
-			postData.UntaggedVlan = netboxVlanId
-		} else {
-			vlan := e.createVlan(netboxSiteId, netboxTenantId, vid, port.Name)
-			*netboxVlansForSite = append(*netboxVlansForSite, vlan)
-		}
+		postData.UntaggedVlan = e.getOrCreateVlan(netboxVlansForSite, netboxSiteId, netboxTenantId, vid, getVlanName(port, vid))
+	}
 
-		if port.VlanMode != "" {
-			postData.Mode = port.VlanMode
-		}
+	if port.VlanMode != "" {
+		postData.Mode = port.VlanMode
+	}
+
+	if len(port.TaggedVlans) != 0 {
+		postData.TaggedVlans = e.getTaggedVlanIds(port.TaggedVlans, netboxVlansForSite, netboxSiteId, netboxTenantId)
 	}
 
+	if port.Vrf != "" {
+		postData.Vrf = e.getOrCreateVrf(netboxVrfs, port.Vrf, netboxTenantId)
+	}
+
+	if port.Vdc != "" {
+		postData.Vdcs = []int{e.getOrCreateVdc(netboxVdcs, port.Vdc, postData.Device, netboxTenantId)}
+	}
+
+	postData.Label = port.Label
+	postData.MgmtOnly = port.MgmtOnly
+
 	if port.PortType == "physical" {
-		postData.InterfaceType = "1000base-t"
+		postData.InterfaceType = port.PortTypeUpdate
 	}
+	postData.Speed = port.Speed
+	postData.Duplex = port.Duplex
 
 	if port.Status != "" {
 		if port.Status == "disabled" {
@@ -417,6 +799,18 @@ func (e *NetboxHTTPClient) createInterface(port model.NetboxInterfaceUpdateCreat
 	}
 
 	postData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
+	if port.Zone != nil && *port.Zone != "" {
+		if zoneTag := e.getOrCreateZoneTag(*port.Zone); zoneTag != 0 {
+			postData.Tags = append(postData.Tags, strconv.Itoa(zoneTag))
+		}
+	}
+	if port.SdwanZone != nil && *port.SdwanZone != "" {
+		if sdwanTag := e.getOrCreateSdwanTag(*port.SdwanZone); sdwanTag != 0 {
+			postData.Tags = append(postData.Tags, strconv.Itoa(sdwanTag))
+		}
+	}
+
+	postData.CustomFields = e.sdwanCustomFields(port)
 
 	data, _ := json.Marshal(postData)
 	requestURL := fmt.Sprintf("%s/%s", e.baseurl, "api/dcim/interfaces/")
@@ -427,7 +821,7 @@ func (e *NetboxHTTPClient) createInterface(port model.NetboxInterfaceUpdateCreat
 	}
 }
 
-func (e *NetboxHTTPClient) UpdateOrCreateInferface(interfaces *[]model.NetboxInterfaceUpdateCreate, netboxVlansForSite *[]model.NetboxVlan, netboxSiteId int, netboxTenantId int) {
+func (e *NetboxHTTPClient) UpdateOrCreateInferface(interfaces *[]model.NetboxInterfaceUpdateCreate, netboxVlansForSite *[]model.NetboxVlan, netboxVrfs *[]model.NetboxVrf, netboxVdcs *[]model.NetboxVdc, netboxSiteId int, netboxTenantId int) {
 	var devicesWithParent []model.NetboxInterfaceUpdateCreate
 	var lagInterfaces []model.NetboxInterfaceUpdateCreate
 	var standalone []model.NetboxInterfaceUpdateCreate
@@ -446,29 +840,134 @@ func (e *NetboxHTTPClient) UpdateOrCreateInferface(interfaces *[]model.NetboxInt
 
 	for _, port := range lagInterfaces {
 		if port.Mode == "create" {
-			e.createInterface(port, netboxVlansForSite, netboxSiteId, netboxTenantId)
+			e.createInterface(port, netboxVlansForSite, netboxVrfs, netboxVdcs, netboxSiteId, netboxTenantId)
 		}
 		if port.Mode == "update" {
-			e.updateInterface(port, netboxVlansForSite, netboxSiteId, netboxTenantId)
+			e.updateInterface(port, netboxVlansForSite, netboxVrfs, netboxVdcs, netboxSiteId, netboxTenantId)
 		}
 	}
 
 	for _, port := range devicesWithParent {
 		if port.Mode == "create" {
-			e.createInterface(port, netboxVlansForSite, netboxSiteId, netboxTenantId)
+			e.createInterface(port, netboxVlansForSite, netboxVrfs, netboxVdcs, netboxSiteId, netboxTenantId)
 		}
 		if port.Mode == "update" {
-			e.updateInterface(port, netboxVlansForSite, netboxSiteId, netboxTenantId)
+			e.updateInterface(port, netboxVlansForSite, netboxVrfs, netboxVdcs, netboxSiteId, netboxTenantId)
 		}
 	}
 
 	for _, port := range standalone {
 
 		if port.Mode == "create" {
-			e.createInterface(port, netboxVlansForSite, netboxSiteId, netboxTenantId)
+			e.createInterface(port, netboxVlansForSite, netboxVrfs, netboxVdcs, netboxSiteId, netboxTenantId)
 		}
 		if port.Mode == "update" {
-			e.updateInterface(port, netboxVlansForSite, netboxSiteId, netboxTenantId)
+			e.updateInterface(port, netboxVlansForSite, netboxVrfs, netboxVdcs, netboxSiteId, netboxTenantId)
+		}
+	}
+}
+
+func (e *NetboxHTTPClient) getL2vpns() ([]model.NetboxL2vpn, error) {
+	requestURL := fmt.Sprintf("%s/api/vpn/l2vpns/", e.baseurl)
+	l2vpns, err := apiRequest[model.NetboxL2vpn](requestURL, e)
+	if err != nil {
+		return []model.NetboxL2vpn{}, err
+	}
+	return l2vpns, nil
+}
+
+func (e *NetboxHTTPClient) createL2vpn(vni int, netboxTenantId int) model.NetboxL2vpn {
+	var postData l2vpnPostData
+	postData.Name = fmt.Sprintf("VNI%d", vni)
+	postData.Slug = slugify(postData.Name)
+	postData.Type = "vxlan"
+	postData.Identifier = vni
+	postData.TenantId = netboxTenantId
+	postData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
+
+	data, _ := json.Marshal(postData)
+	requestURL := fmt.Sprintf("%s/api/vpn/l2vpns/", e.baseurl)
+	resBody, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+
+	var result model.NetboxL2vpn
+	err = json.Unmarshal(resBody, &result)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+	return result
+}
+
+// getOrCreateL2vpn looks up the L2VPN of the vni under the create lock, another worker
+// can have created it after the L2VPNs were fetched.
+func (e *NetboxHTTPClient) getOrCreateL2vpn(vni int, netboxTenantId int) model.NetboxL2vpn {
+	e.createLock.Lock()
+	defer e.createLock.Unlock()
+
+	requestURL := fmt.Sprintf("%s/api/vpn/l2vpns/?identifier=%d", e.baseurl, vni)
+	l2vpns, err := apiRequest[model.NetboxL2vpn](requestURL, e)
+	if err != nil {
+		slog.Error(err.Error())
+		return model.NetboxL2vpn{}
+	}
+	if len(l2vpns) > 0 {
+		return l2vpns[0]
+	}
+	return e.createL2vpn(vni, netboxTenantId)
+}
+
+func (e *NetboxHTTPClient) createL2vpnTermination(l2vpnId int, vlanId int) {
+	var postData l2vpnTerminationPostData
+	postData.L2vpn = l2vpnId
+	postData.AssignedObjectType = "ipam.vlan"
+	postData.AssignedObjectId = vlanId
+	postData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
+
+	data, _ := json.Marshal(postData)
+	requestURL := fmt.Sprintf("%s/api/vpn/l2vpn-terminations/", e.baseurl)
+	_, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+}
+
+// SyncVxlanMappings creates a vxlan L2VPN per VNI and terminates it on the mapped vlan of the site.
+func (e *NetboxHTTPClient) SyncVxlanMappings(vniMappings map[string]string, netboxVlansForSite *[]model.NetboxVlan, netboxSiteId int, netboxTenantId int) {
+	l2vpns, err := e.getL2vpns()
+	if err != nil {
+		slog.Error(err.Error())
+		return
+	}
+
+	for vlanId, vniId := range vniMappings {
+		vid, err := strconv.Atoi(vlanId)
+		if err != nil {
+			continue
+		}
+		vni, err := strconv.Atoi(vniId)
+		if err != nil {
+			continue
+		}
+
+		idx := slices.IndexFunc(l2vpns, func(l model.NetboxL2vpn) bool { return l.Identifier == vni })
+		var l2vpn model.NetboxL2vpn
+		if idx == -1 {
+			l2vpn = e.getOrCreateL2vpn(vni, netboxTenantId)
+			l2vpns = append(l2vpns, l2vpn)
+		} else {
+			l2vpn = l2vpns[idx]
+		}
+		if l2vpn.ID == 0 {
+			continue
+		}
+
+		netboxVlanId := e.getOrCreateVlan(netboxVlansForSite, netboxSiteId, netboxTenantId, vid, defaultVlanName(vid))
+		vlanIdx := slices.IndexFunc(*netboxVlansForSite, func(v model.NetboxVlan) bool { return v.ID == netboxVlanId })
+		if netboxVlanId == 0 || (vlanIdx != -1 && (*netboxVlansForSite)[vlanIdx].L2VpnTermination != nil) {
+			continue
 		}
+		e.createL2vpnTermination(l2vpn.ID, netboxVlanId)
 	}
 }
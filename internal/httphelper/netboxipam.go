@@ -0,0 +1,448 @@
+package httphelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const (
+	interfaceObjectType = "dcim.interface"
+	fhrpGroupObjectType = "ipam.fhrpgroup"
+)
+
+type ipAddressPostData struct {
+	Address            string   `json:"address"`
+	Status             string   `json:"status"`
+	Vrf                int      `json:"vrf,omitempty"`
+	TenantId           int      `json:"tenant,omitempty"`
+	Role               string   `json:"role,omitempty"`
+	AssignedObjectType string   `json:"assigned_object_type"`
+	AssignedObjectId   int      `json:"assigned_object_id"`
+	Description        string   `json:"description,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+}
+
+type ipAddressPatchData struct {
+	Vrf                int    `json:"vrf,omitempty"`
+	AssignedObjectType string `json:"assigned_object_type,omitempty"`
+	AssignedObjectId   int    `json:"assigned_object_id,omitempty"`
+	Description        string `json:"description,omitempty"`
+}
+
+type fhrpGroupPostData struct {
+	Protocol string   `json:"protocol"`
+	GroupId  int      `json:"group_id"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+type fhrpGroupAssignmentPostData struct {
+	Group         int    `json:"group"`
+	InterfaceType string `json:"interface_type"`
+	InterfaceId   int    `json:"interface_id"`
+	Priority      int    `json:"priority"`
+}
+
+type fhrpGroupAssignmentPatchData struct {
+	Priority int `json:"priority"`
+}
+
+type devicePrimaryIPPatchData struct {
+	PrimaryIP4 int `json:"primary_ip4,omitempty"`
+	PrimaryIP6 int `json:"primary_ip6,omitempty"`
+}
+
+type prefixPostData struct {
+	Prefix   string   `json:"prefix"`
+	Status   string   `json:"status"`
+	SiteId   int      `json:"site,omitempty"`
+	TenantId int      `json:"tenant,omitempty"`
+	Vrf      int      `json:"vrf,omitempty"`
+	Vlan     int      `json:"vlan,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+type prefixPatchData struct {
+	SiteId   int             `json:"site,omitempty"`
+	TenantId int             `json:"tenant,omitempty"`
+	Vrf      json.RawMessage `json:"vrf,omitempty"`
+	Vlan     int             `json:"vlan,omitempty"`
+}
+
+// ManagedTagID returns the id of the tag used to track the objects created by the sync.
+func (e *NetboxHTTPClient) ManagedTagID() int {
+	return e.defaultTag.ID
+}
+
+func (e *NetboxHTTPClient) GetIPAddressesForDevice(deviceId string) ([]model.NetboxIPAddress, error) {
+	requestURL := fmt.Sprintf("%s/api/ipam/ip-addresses/?device_id=%s", e.baseurl, deviceId)
+	ipAddresses, err := apiRequest[model.NetboxIPAddress](requestURL, e)
+	if err != nil {
+		return []model.NetboxIPAddress{}, err
+	}
+	return ipAddresses, nil
+}
+
+// GetIPAddresses returns the ip addresses with the given addresses, in any vrf and
+// on any device.
+func (e *NetboxHTTPClient) GetIPAddresses(addresses []string) ([]model.NetboxIPAddress, error) {
+	if len(addresses) == 0 {
+		return []model.NetboxIPAddress{}, nil
+	}
+
+	var filter []string
+	for _, address := range addresses {
+		filter = append(filter, fmt.Sprintf("address=%s", url.QueryEscape(address)))
+	}
+	requestURL := fmt.Sprintf("%s/api/ipam/ip-addresses/?%s", e.baseurl, strings.Join(filter, "&"))
+	ipAddresses, err := apiRequest[model.NetboxIPAddress](requestURL, e)
+	if err != nil {
+		return []model.NetboxIPAddress{}, err
+	}
+	return ipAddresses, nil
+}
+
+func (e *NetboxHTTPClient) createIPAddress(ip model.NetboxIPAddressUpdateCreate, netboxVrfs *[]model.NetboxVrf, netboxTenantId int) {
+	var postData ipAddressPostData
+	postData.Address = ip.Address
+	postData.Status = "active"
+	postData.TenantId = netboxTenantId
+	postData.AssignedObjectType = interfaceObjectType
+	postData.AssignedObjectId, _ = strconv.Atoi(ip.InterfaceId)
+	postData.Description = ip.Description
+	postData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
+
+	if ip.Vrf != "" {
+		postData.Vrf = e.getOrCreateVrf(netboxVrfs, ip.Vrf, netboxTenantId)
+	}
+
+	data, _ := json.Marshal(postData)
+	requestURL := fmt.Sprintf("%s/api/ipam/ip-addresses/", e.baseurl)
+	_, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not create ip address", "address", ip.Address, "error", err)
+	}
+}
+
+func (e *NetboxHTTPClient) updateIPAddress(ip model.NetboxIPAddressUpdateCreate, netboxVrfs *[]model.NetboxVrf, netboxTenantId int) {
+	var patchData ipAddressPatchData
+
+	if ip.InterfaceId != "" {
+		patchData.AssignedObjectType = interfaceObjectType
+		patchData.AssignedObjectId, _ = strconv.Atoi(ip.InterfaceId)
+	}
+	if ip.Vrf != "" {
+		patchData.Vrf = e.getOrCreateVrf(netboxVrfs, ip.Vrf, netboxTenantId)
+	}
+	patchData.Description = ip.Description
+
+	data, _ := json.Marshal(patchData)
+	requestURL := fmt.Sprintf("%s/api/ipam/ip-addresses/%s/", e.baseurl, ip.IPAddressId)
+	_, err := TokenAuthHTTPPatch(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not update ip address", "address", ip.Address, "error", err)
+	}
+}
+
+func (e *NetboxHTTPClient) deleteIPAddress(ip model.NetboxIPAddressUpdateCreate) {
+	requestURL := fmt.Sprintf("%s/api/ipam/ip-addresses/%s/", e.baseurl, ip.IPAddressId)
+	err := TokenAuthHTTPDelete(requestURL, e.apikey, &e.client)
+	if err != nil {
+		slog.Error("Could not delete ip address", "address", ip.Address, "error", err)
+	}
+}
+
+func (e *NetboxHTTPClient) UpdateOrCreateIPAddresses(ipAddresses *[]model.NetboxIPAddressUpdateCreate, netboxVrfs *[]model.NetboxVrf, netboxTenantId int) {
+	// remove the stale addresses first, the address can be moved to another interface
+	for _, ip := range *ipAddresses {
+		if ip.Mode == "delete" {
+			e.deleteIPAddress(ip)
+		}
+	}
+
+	for _, ip := range *ipAddresses {
+		switch ip.Mode {
+		case "create":
+			e.createIPAddress(ip, netboxVrfs, netboxTenantId)
+		case "update":
+			e.updateIPAddress(ip, netboxVrfs, netboxTenantId)
+		}
+	}
+}
+
+// UpdatePrimaryIPs sets the primary ipv4 and ipv6 address of the device, an id of 0
+// leaves the current primary address.
+func (e *NetboxHTTPClient) UpdatePrimaryIPs(netboxDevice model.NetboxDevice, primaryIP4 int, primaryIP6 int) {
+	if primaryIP4 == 0 && primaryIP6 == 0 {
+		return
+	}
+
+	patchData := devicePrimaryIPPatchData{primaryIP4, primaryIP6}
+	data, _ := json.Marshal(patchData)
+	requestURL := fmt.Sprintf("%s/api/dcim/devices/%d/", e.baseurl, netboxDevice.ID)
+	_, err := TokenAuthHTTPPatch(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not update primary ip", "device", netboxDevice.Name, "error", err)
+	}
+}
+
+// GetPrefixesForSite returns the prefixes of the site and the global prefixes without a site.
+func (e *NetboxHTTPClient) GetPrefixesForSite(siteId string) ([]model.NetboxPrefix, error) {
+	var result []model.NetboxPrefix
+	for _, site := range []string{siteId, "null"} {
+		requestURL := fmt.Sprintf("%s/api/ipam/prefixes/?site_id=%s", e.baseurl, site)
+		prefixes, err := apiRequest[model.NetboxPrefix](requestURL, e)
+		if err != nil {
+			return []model.NetboxPrefix{}, err
+		}
+		result = append(result, prefixes...)
+	}
+	return result, nil
+}
+
+func (e *NetboxHTTPClient) getPrefixVlan(prefix model.NetboxPrefixUpdateCreate, netboxVlansForSite *[]model.NetboxVlan, netboxSiteId int, netboxTenantId int) int {
+	vid, err := strconv.Atoi(prefix.VlanId)
+	if err != nil {
+		return 0
+	}
+	return e.getOrCreateVlan(netboxVlansForSite, netboxSiteId, netboxTenantId, vid, prefix.VlanName)
+}
+
+func (e *NetboxHTTPClient) createPrefix(prefix model.NetboxPrefixUpdateCreate, netboxVlansForSite *[]model.NetboxVlan, netboxVrfs *[]model.NetboxVrf, netboxSiteId int, netboxTenantId int) {
+	var postData prefixPostData
+	postData.Prefix = prefix.Prefix
+	postData.Status = "active"
+	postData.SiteId = netboxSiteId
+	postData.TenantId = netboxTenantId
+	postData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
+
+	if prefix.Vrf != "" {
+		postData.Vrf = e.getOrCreateVrf(netboxVrfs, prefix.Vrf, netboxTenantId)
+	}
+	if prefix.VlanId != "" {
+		postData.Vlan = e.getPrefixVlan(prefix, netboxVlansForSite, netboxSiteId, netboxTenantId)
+	}
+
+	data, _ := json.Marshal(postData)
+	requestURL := fmt.Sprintf("%s/api/ipam/prefixes/", e.baseurl)
+	_, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not create prefix", "prefix", prefix.Prefix, "error", err)
+	}
+}
+
+func (e *NetboxHTTPClient) updatePrefix(prefix model.NetboxPrefixUpdateCreate, netboxVlansForSite *[]model.NetboxVlan, netboxVrfs *[]model.NetboxVrf, netboxSiteId int, netboxTenantId int) {
+	var patchData prefixPatchData
+	patchData.SiteId = prefix.SiteId
+	patchData.TenantId = prefix.TenantId
+	if prefix.VrfChanged {
+		// a prefix that moves to the global table needs an explicit null vrf
+		patchData.Vrf = json.RawMessage("null")
+		if prefix.Vrf != "" {
+			patchData.Vrf = json.RawMessage(strconv.Itoa(e.getOrCreateVrf(netboxVrfs, prefix.Vrf, netboxTenantId)))
+		}
+	}
+	if prefix.VlanId != "" {
+		patchData.Vlan = e.getPrefixVlan(prefix, netboxVlansForSite, netboxSiteId, netboxTenantId)
+	}
+
+	data, _ := json.Marshal(patchData)
+	requestURL := fmt.Sprintf("%s/api/ipam/prefixes/%s/", e.baseurl, prefix.PrefixId)
+	_, err := TokenAuthHTTPPatch(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not update prefix", "prefix", prefix.Prefix, "error", err)
+	}
+}
+
+func (e *NetboxHTTPClient) UpdateOrCreatePrefixes(prefixes *[]model.NetboxPrefixUpdateCreate, netboxVlansForSite *[]model.NetboxVlan, netboxVrfs *[]model.NetboxVrf, netboxSiteId int, netboxTenantId int) {
+	for _, prefix := range *prefixes {
+		switch prefix.Mode {
+		case "create":
+			e.createPrefix(prefix, netboxVlansForSite, netboxVrfs, netboxSiteId, netboxTenantId)
+		case "update":
+			e.updatePrefix(prefix, netboxVlansForSite, netboxVrfs, netboxSiteId, netboxTenantId)
+		}
+	}
+}
+
+func (e *NetboxHTTPClient) GetAllFhrpGroups() ([]model.NetboxFhrpGroup, error) {
+	requestURL := fmt.Sprintf("%s/api/ipam/fhrp-groups/", e.baseurl)
+	fhrpGroups, err := apiRequest[model.NetboxFhrpGroup](requestURL, e)
+	if err != nil {
+		return []model.NetboxFhrpGroup{}, err
+	}
+	return fhrpGroups, nil
+}
+
+func (e *NetboxHTTPClient) GetFhrpGroupAssignmentsForDevice(deviceId string) ([]model.NetboxFhrpGroupAssignment, error) {
+	requestURL := fmt.Sprintf("%s/api/ipam/fhrp-group-assignments/?device_id=%s", e.baseurl, deviceId)
+	assignments, err := apiRequest[model.NetboxFhrpGroupAssignment](requestURL, e)
+	if err != nil {
+		return []model.NetboxFhrpGroupAssignment{}, err
+	}
+	return assignments, nil
+}
+
+// fhrpAddressRole returns the ip address role NetBox uses for the virtual ip of the protocol.
+func fhrpAddressRole(protocol string) string {
+	switch protocol {
+	case "hsrp":
+		return "hsrp"
+	case "vrrp2", "vrrp3":
+		return "vrrp"
+	}
+	return "vip"
+}
+
+// findFhrpGroup returns the id of the NetBox group with the protocol and group id that
+// has one of the virtual ips, the groups are fetched again as the peer of the device
+// can be synced by another worker.
+func (e *NetboxHTTPClient) findFhrpGroup(group model.NetboxFhrpGroupUpdateCreate) (int, error) {
+	requestURL := fmt.Sprintf("%s/api/ipam/fhrp-groups/?protocol=%s&group_id=%s", e.baseurl, group.Protocol, group.GroupId)
+	fhrpGroups, err := apiRequest[model.NetboxFhrpGroup](requestURL, e)
+	if err != nil {
+		return 0, err
+	}
+	for _, fhrpGroup := range fhrpGroups {
+		for _, ipAddress := range fhrpGroup.IPAddresses {
+			address, err := netip.ParsePrefix(ipAddress.Address)
+			if err != nil {
+				continue
+			}
+			for _, virtualIP := range group.VirtualIPs {
+				if virtual, err := netip.ParsePrefix(virtualIP); err == nil && virtual.Addr() == address.Addr() {
+					return fhrpGroup.ID, nil
+				}
+			}
+		}
+	}
+	return 0, nil
+}
+
+// getOrCreateFhrpGroup creates the group with its virtual ips unless another worker
+// created it after the groups were fetched.
+func (e *NetboxHTTPClient) getOrCreateFhrpGroup(group model.NetboxFhrpGroupUpdateCreate, vrfId int, netboxTenantId int) int {
+	e.createLock.Lock()
+	defer e.createLock.Unlock()
+
+	fhrpGroupId, err := e.findFhrpGroup(group)
+	if err != nil {
+		slog.Error("Could not get fhrp groups", "protocol", group.Protocol, "group", group.GroupId, "error", err)
+		return 0
+	}
+	if fhrpGroupId != 0 {
+		return fhrpGroupId
+	}
+	return e.createFhrpGroup(group, vrfId, netboxTenantId)
+}
+
+func (e *NetboxHTTPClient) createFhrpGroup(group model.NetboxFhrpGroupUpdateCreate, vrfId int, netboxTenantId int) int {
+	var postData fhrpGroupPostData
+	postData.Protocol = group.Protocol
+	postData.GroupId, _ = strconv.Atoi(group.GroupId)
+	postData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
+
+	data, _ := json.Marshal(postData)
+	requestURL := fmt.Sprintf("%s/api/ipam/fhrp-groups/", e.baseurl)
+	resBody, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not create fhrp group", "protocol", group.Protocol, "group", group.GroupId, "error", err)
+		return 0
+	}
+
+	var result model.NetboxFhrpGroup
+	err = json.Unmarshal(resBody, &result)
+	if err != nil {
+		slog.Error(err.Error())
+		return 0
+	}
+
+	for _, virtualIP := range group.VirtualIPs {
+		var ipPostData ipAddressPostData
+		ipPostData.Address = virtualIP
+		ipPostData.Status = "active"
+		ipPostData.Role = fhrpAddressRole(group.Protocol)
+		ipPostData.TenantId = netboxTenantId
+		ipPostData.AssignedObjectType = fhrpGroupObjectType
+		ipPostData.AssignedObjectId = result.ID
+		ipPostData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
+		ipPostData.Vrf = vrfId
+
+		data, _ := json.Marshal(ipPostData)
+		requestURL := fmt.Sprintf("%s/api/ipam/ip-addresses/", e.baseurl)
+		_, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+		if err != nil {
+			slog.Error("Could not create virtual ip", "address", virtualIP, "error", err)
+		}
+	}
+
+	return result.ID
+}
+
+func (e *NetboxHTTPClient) createFhrpGroupAssignment(fhrpGroupId int, group model.NetboxFhrpGroupUpdateCreate) {
+	var postData fhrpGroupAssignmentPostData
+	postData.Group = fhrpGroupId
+	postData.InterfaceType = interfaceObjectType
+	postData.InterfaceId, _ = strconv.Atoi(group.InterfaceId)
+	postData.Priority, _ = strconv.Atoi(group.Priority)
+
+	data, _ := json.Marshal(postData)
+	requestURL := fmt.Sprintf("%s/api/ipam/fhrp-group-assignments/", e.baseurl)
+	_, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not assign fhrp group", "protocol", group.Protocol, "group", group.GroupId, "error", err)
+	}
+}
+
+func (e *NetboxHTTPClient) updateFhrpGroupAssignment(group model.NetboxFhrpGroupUpdateCreate) {
+	var patchData fhrpGroupAssignmentPatchData
+	patchData.Priority, _ = strconv.Atoi(group.Priority)
+
+	data, _ := json.Marshal(patchData)
+	requestURL := fmt.Sprintf("%s/api/ipam/fhrp-group-assignments/%s/", e.baseurl, group.AssignmentId)
+	_, err := TokenAuthHTTPPatch(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not update fhrp group assignment", "protocol", group.Protocol, "group", group.GroupId, "error", err)
+	}
+}
+
+func (e *NetboxHTTPClient) deleteFhrpGroupAssignment(group model.NetboxFhrpGroupUpdateCreate) {
+	requestURL := fmt.Sprintf("%s/api/ipam/fhrp-group-assignments/%s/", e.baseurl, group.AssignmentId)
+	err := TokenAuthHTTPDelete(requestURL, e.apikey, &e.client)
+	if err != nil {
+		slog.Error("Could not delete fhrp group assignment", "protocol", group.Protocol, "group", group.GroupId, "error", err)
+	}
+}
+
+func (e *NetboxHTTPClient) UpdateOrCreateFhrpGroups(fhrpGroups *[]model.NetboxFhrpGroupUpdateCreate, netboxVrfs *[]model.NetboxVrf, netboxTenantId int) {
+	for _, group := range *fhrpGroups {
+		if group.Mode == "delete" {
+			e.deleteFhrpGroupAssignment(group)
+		}
+	}
+
+	for _, group := range *fhrpGroups {
+		switch group.Mode {
+		case "create":
+			fhrpGroupId, _ := strconv.Atoi(group.FhrpGroupId)
+			if fhrpGroupId == 0 {
+				var vrfId int
+				if group.Vrf != "" {
+					vrfId = e.getOrCreateVrf(netboxVrfs, group.Vrf, netboxTenantId)
+				}
+				fhrpGroupId = e.getOrCreateFhrpGroup(group, vrfId, netboxTenantId)
+			}
+			if fhrpGroupId != 0 {
+				e.createFhrpGroupAssignment(fhrpGroupId, group)
+			}
+		case "update":
+			e.updateFhrpGroupAssignment(group)
+		}
+	}
+}
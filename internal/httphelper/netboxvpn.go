@@ -0,0 +1,143 @@
+package httphelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+type tunnelPostData struct {
+	Name          string   `json:"name"`
+	Status        string   `json:"status"`
+	Encapsulation string   `json:"encapsulation"`
+	TenantId      int      `json:"tenant,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+type tunnelPatchData struct {
+	Encapsulation string `json:"encapsulation,omitempty"`
+	Description   string `json:"description,omitempty"`
+}
+
+type tunnelTerminationPostData struct {
+	Tunnel          int      `json:"tunnel"`
+	Role            string   `json:"role"`
+	TerminationType string   `json:"termination_type"`
+	TerminationId   int      `json:"termination_id"`
+	OutsideIP       int      `json:"outside_ip,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+}
+
+func (e *NetboxHTTPClient) GetAllTunnels() ([]model.NetboxTunnel, error) {
+	requestURL := fmt.Sprintf("%s/api/vpn/tunnels/", e.baseurl)
+	tunnels, err := apiRequest[model.NetboxTunnel](requestURL, e)
+	if err != nil {
+		return []model.NetboxTunnel{}, err
+	}
+	return tunnels, nil
+}
+
+// GetTerminationsForInterfaces returns the tunnel terminations on the given interfaces,
+// only the ids of the tunnel interfaces are passed to keep the url short.
+func (e *NetboxHTTPClient) GetTerminationsForInterfaces(interfaceIds []string) ([]model.NetboxTunnelTermination, error) {
+	if len(interfaceIds) == 0 {
+		return []model.NetboxTunnelTermination{}, nil
+	}
+
+	var filter []string
+	for _, interfaceId := range interfaceIds {
+		filter = append(filter, fmt.Sprintf("interface_id=%s", interfaceId))
+	}
+	requestURL := fmt.Sprintf("%s/api/vpn/tunnel-terminations/?%s", e.baseurl, strings.Join(filter, "&"))
+	terminations, err := apiRequest[model.NetboxTunnelTermination](requestURL, e)
+	if err != nil {
+		return []model.NetboxTunnelTermination{}, err
+	}
+	return terminations, nil
+}
+
+func (e *NetboxHTTPClient) createTunnel(tunnel model.NetboxTunnelUpdateCreate, netboxTenantId int) int {
+	var postData tunnelPostData
+	postData.Name = tunnel.Name
+	postData.Status = "active"
+	postData.Encapsulation = tunnel.Encapsulation
+	postData.TenantId = netboxTenantId
+	postData.Description = tunnel.Description
+	postData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
+
+	data, _ := json.Marshal(postData)
+	requestURL := fmt.Sprintf("%s/api/vpn/tunnels/", e.baseurl)
+	resBody, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not create tunnel", "tunnel", tunnel.Name, "error", err)
+		return 0
+	}
+
+	var result model.NetboxTunnel
+	err = json.Unmarshal(resBody, &result)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+	return result.ID
+}
+
+func (e *NetboxHTTPClient) updateTunnel(tunnel model.NetboxTunnelUpdateCreate) {
+	var patchData tunnelPatchData
+	patchData.Encapsulation = tunnel.Encapsulation
+	patchData.Description = tunnel.Description
+
+	data, _ := json.Marshal(patchData)
+	requestURL := fmt.Sprintf("%s/api/vpn/tunnels/%s/", e.baseurl, tunnel.TunnelId)
+	_, err := TokenAuthHTTPPatch(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not update tunnel", "tunnel", tunnel.Name, "error", err)
+	}
+}
+
+func (e *NetboxHTTPClient) createTunnelTermination(tunnelId int, tunnel model.NetboxTunnelUpdateCreate, interfaceId string, outsideIPId string) {
+	var postData tunnelTerminationPostData
+	postData.Tunnel = tunnelId
+	postData.Role = "peer"
+	postData.TerminationType = interfaceObjectType
+	postData.TerminationId, _ = strconv.Atoi(interfaceId)
+	postData.OutsideIP, _ = strconv.Atoi(outsideIPId)
+	postData.Tags = []string{strconv.Itoa(e.defaultTag.ID)}
+
+	data, _ := json.Marshal(postData)
+	requestURL := fmt.Sprintf("%s/api/vpn/tunnel-terminations/", e.baseurl)
+	_, err := TokenAuthHTTPPost(requestURL, e.apikey, &e.client, data)
+	if err != nil {
+		slog.Error("Could not create tunnel termination", "tunnel", tunnel.Name, "error", err)
+	}
+}
+
+func (e *NetboxHTTPClient) UpdateOrCreateTunnels(tunnels *[]model.NetboxTunnelUpdateCreate, netboxTenantId int) {
+	for _, tunnel := range *tunnels {
+		switch tunnel.Mode {
+		case "create":
+			tunnelId := e.createTunnel(tunnel, netboxTenantId)
+			if tunnelId != 0 {
+				e.createTunnelTermination(tunnelId, tunnel, tunnel.InterfaceId, tunnel.OutsideIPId)
+				if tunnel.PeerInterfaceId != "" {
+					e.createTunnelTermination(tunnelId, tunnel, tunnel.PeerInterfaceId, tunnel.PeerOutsideIPId)
+				}
+			}
+		case "update":
+			if tunnel.Encapsulation != "" || tunnel.Description != "" {
+				e.updateTunnel(tunnel)
+			}
+			tunnelId, _ := strconv.Atoi(tunnel.TunnelId)
+			if tunnel.InterfaceId != "" {
+				e.createTunnelTermination(tunnelId, tunnel, tunnel.InterfaceId, tunnel.OutsideIPId)
+			}
+			if tunnel.PeerInterfaceId != "" {
+				e.createTunnelTermination(tunnelId, tunnel, tunnel.PeerInterfaceId, tunnel.PeerOutsideIPId)
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import "time"
 
 type FortigateInterface struct {
 	Name          string
+	ObjectName    string // the name the config references, Name can be an alias
 	Members       []string
 	Description   string
 	Status        string
@@ -11,6 +12,34 @@ type FortigateInterface struct {
 	VlanId        string
 	Parent        string
 	InterfaceType string
+	VlanMode      string
+	TaggedVlans   []string
+	Vrf           string
+	VniMappings   map[string]string
+	IPAddresses   []string
+	Zone          string
+	SdwanZone     string
+	SdwanGateway  string
+	SdwanPriority string
+	Vdom          string
+	Label         string
+	MgmtOnly      *bool
+	StandbyIPs    []string
+	FhrpGroups    []FhrpGroup
+	Tunnel        *Tunnel
+}
+
+type Tunnel struct {
+	Encapsulation string
+	LocalGateway  string
+	RemoteGateway string
+}
+
+type FhrpGroup struct {
+	Protocol   string
+	GroupId    string
+	VirtualIPs []string
+	Priority   string
 }
 
 type NetboxInterface struct {
@@ -24,7 +53,12 @@ type NetboxInterface struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 	} `json:"device"`
-	Vdcs   []interface{} `json:"vdcs"`
+	Vdcs   []struct {
+		ID      int    `json:"id"`
+		URL     string `json:"url"`
+		Display string `json:"display"`
+		Name    string `json:"name"`
+	} `json:"vdcs"`
 	Module interface{}   `json:"module"`
 	Name   string        `json:"name"`
 	Label  string        `json:"label"`
@@ -57,8 +91,11 @@ type NetboxInterface struct {
 	} `json:"lag"`
 	Mtu         interface{} `json:"mtu"`
 	MacAddress  interface{} `json:"mac_address"`
-	Speed       interface{} `json:"speed"`
-	Duplex      interface{} `json:"duplex"`
+	Speed       *int        `json:"speed"`
+	Duplex      struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	} `json:"duplex"`
 	Wwn         interface{} `json:"wwn"`
 	MgmtOnly    bool        `json:"mgmt_only"`
 	Description string      `json:"description"`
@@ -81,7 +118,11 @@ type NetboxInterface struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 	} `json:"untagged_vlan"`
-	TaggedVlans                 []interface{} `json:"tagged_vlans"`
+	TaggedVlans                 []struct {
+		ID   int    `json:"id"`
+		Vid  int    `json:"vid"`
+		Name string `json:"name"`
+	} `json:"tagged_vlans"`
 	MarkConnected               bool          `json:"mark_connected"`
 	Cable                       interface{}   `json:"cable"`
 	CableEnd                    string        `json:"cable_end"`
@@ -89,7 +130,10 @@ type NetboxInterface struct {
 	LinkPeers                   []interface{} `json:"link_peers"`
 	LinkPeersType               interface{}   `json:"link_peers_type"`
 	WirelessLans                []interface{} `json:"wireless_lans"`
-	Vrf                         interface{}   `json:"vrf"`
+	Vrf                         struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"vrf"`
 	L2VpnTermination            interface{}   `json:"l2vpn_termination"`
 	ConnectedEndpoints          interface{}   `json:"connected_endpoints"`
 	ConnectedEndpointsType      interface{}   `json:"connected_endpoints_type"`
@@ -103,6 +147,8 @@ type NetboxInterface struct {
 		Color   string `json:"color"`
 	} `json:"tags"`
 	CustomFields struct {
+		SdwanGateway  string `json:"sdwan_gateway"`
+		SdwanPriority *int   `json:"sdwan_priority"`
 	} `json:"custom_fields"`
 	Created          time.Time `json:"created"`
 	LastUpdated      time.Time `json:"last_updated"`
@@ -174,8 +220,20 @@ type NetboxDevice struct {
 	} `json:"status"`
 	Airflow        interface{} `json:"airflow"`
 	PrimaryIP      interface{} `json:"primary_ip"`
-	PrimaryIP4     interface{} `json:"primary_ip4"`
-	PrimaryIP6     interface{} `json:"primary_ip6"`
+	PrimaryIP4     struct {
+		ID      int    `json:"id"`
+		URL     string `json:"url"`
+		Display string `json:"display"`
+		Family  int    `json:"family"`
+		Address string `json:"address"`
+	} `json:"primary_ip4"`
+	PrimaryIP6 struct {
+		ID      int    `json:"id"`
+		URL     string `json:"url"`
+		Display string `json:"display"`
+		Family  int    `json:"family"`
+		Address string `json:"address"`
+	} `json:"primary_ip6"`
 	OobIP          interface{} `json:"oob_ip"`
 	Cluster        interface{} `json:"cluster"`
 	VirtualChassis interface{} `json:"virtual_chassis"`
@@ -212,9 +270,16 @@ type NetboxDevice struct {
 	InventoryItemCount     int       `json:"inventory_item_count"`
 }
 
+type ManagedSwitch struct {
+	Serial     string
+	Name       string
+	Interfaces []FortigateInterface
+}
+
 type FortigateVirtualSwitch struct {
 	Name    string
 	Members []string
+	VlanId  string
 }
 
 type NetboxInterfaceUpdateCreate struct {
@@ -230,6 +295,17 @@ type NetboxInterfaceUpdateCreate struct {
 	ParentType     string
 	VlanMode       string
 	VlanId         string
+	TaggedVlans    []string
+	Vrf            string
+	Label          string
+	MgmtOnly       *bool
+	Zone           *string
+	SdwanZone      *string
+	SdwanGateway   *string
+	SdwanPriority  *string
+	Vdc            string
+	Speed          int
+	Duplex         string
 	InterfaceId    string
 	Tags           []string
 	Matched        bool
@@ -274,6 +350,29 @@ type NetboxVlan struct {
 	PrefixCount int       `json:"prefix_count"`
 }
 
+// ZoneTagPrefix is the name prefix of the tags used for the firewall zone of an interface.
+const ZoneTagPrefix = "zone:"
+
+// SdwanTagPrefix is the name prefix of the tags used for the SD-WAN zone of an interface.
+const SdwanTagPrefix = "sdwan:"
+
+// SdwanGatewayField and SdwanPriorityField are the interface custom fields of the
+// gateway and priority of an SD-WAN member.
+const (
+	SdwanGatewayField  = "sdwan_gateway"
+	SdwanPriorityField = "sdwan_priority"
+)
+
+type NetboxCustomField struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Type struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	} `json:"type"`
+	ObjectTypes []string `json:"object_types"`
+}
+
 type NetboxTag struct {
 	ID          int       `json:"id"`
 	URL         string    `json:"url"`
@@ -287,3 +386,297 @@ type NetboxTag struct {
 	Created     time.Time `json:"created"`
 	LastUpdated time.Time `json:"last_updated"`
 }
+
+type NetboxVrf struct {
+	ID            int    `json:"id"`
+	URL           string `json:"url"`
+	Display       string `json:"display"`
+	Name          string `json:"name"`
+	Rd            string `json:"rd"`
+	EnforceUnique bool   `json:"enforce_unique"`
+	Tenant        struct {
+		ID      int    `json:"id"`
+		URL     string `json:"url"`
+		Display string `json:"display"`
+		Name    string `json:"name"`
+		Slug    string `json:"slug"`
+	} `json:"tenant"`
+	Description string        `json:"description"`
+	Tags        []interface{} `json:"tags"`
+	Created     time.Time     `json:"created"`
+	LastUpdated time.Time     `json:"last_updated"`
+}
+
+type NetboxTenant struct {
+	ID          int       `json:"id"`
+	URL         string    `json:"url"`
+	Display     string    `json:"display"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	Created     time.Time `json:"created"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+type NetboxVdc struct {
+	ID         int    `json:"id"`
+	URL        string `json:"url"`
+	Display    string `json:"display"`
+	Name       string `json:"name"`
+	Identifier int    `json:"identifier"`
+	Device     struct {
+		ID      int    `json:"id"`
+		URL     string `json:"url"`
+		Display string `json:"display"`
+		Name    string `json:"name"`
+	} `json:"device"`
+	Status struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	} `json:"status"`
+	Description string        `json:"description"`
+	Tags        []interface{} `json:"tags"`
+	Created     time.Time     `json:"created"`
+	LastUpdated time.Time     `json:"last_updated"`
+}
+
+type NetboxIPAddress struct {
+	ID      int    `json:"id"`
+	URL     string `json:"url"`
+	Display string `json:"display"`
+	Family  struct {
+		Value int    `json:"value"`
+		Label string `json:"label"`
+	} `json:"family"`
+	Address string `json:"address"`
+	Vrf     struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"vrf"`
+	Tenant struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"tenant"`
+	Status struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	} `json:"status"`
+	Role struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	} `json:"role"`
+	AssignedObjectType string `json:"assigned_object_type"`
+	AssignedObjectID   int    `json:"assigned_object_id"`
+	AssignedObject     struct {
+		ID     int    `json:"id"`
+		Name   string `json:"name"`
+		Device struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"device"`
+	} `json:"assigned_object"`
+	DNSName     string `json:"dns_name"`
+	Description string `json:"description"`
+	Tags        []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	} `json:"tags"`
+	Created     time.Time `json:"created"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+type NetboxIPAddressUpdateCreate struct {
+	Mode        string
+	IPAddressId string
+	Address     string
+	InterfaceId string
+	Vrf         string
+	Description string
+}
+
+type NetboxPrefix struct {
+	ID      int    `json:"id"`
+	URL     string `json:"url"`
+	Display string `json:"display"`
+	Family  struct {
+		Value int    `json:"value"`
+		Label string `json:"label"`
+	} `json:"family"`
+	Prefix string `json:"prefix"`
+	Site   struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"site"`
+	Vrf struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"vrf"`
+	Tenant struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"tenant"`
+	Vlan struct {
+		ID   int    `json:"id"`
+		Vid  int    `json:"vid"`
+		Name string `json:"name"`
+	} `json:"vlan"`
+	Status struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	} `json:"status"`
+	Description string `json:"description"`
+	Tags        []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	} `json:"tags"`
+	Created     time.Time `json:"created"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+type NetboxPrefixUpdateCreate struct {
+	Mode       string
+	PrefixId   string
+	Prefix     string
+	Vrf        string
+	VrfChanged bool
+	SiteId     int
+	TenantId   int
+	VlanId     string
+	VlanName   string
+}
+
+type NetboxFhrpGroup struct {
+	ID          int    `json:"id"`
+	URL         string `json:"url"`
+	Display     string `json:"display"`
+	Name        string `json:"name"`
+	Protocol    string `json:"protocol"`
+	GroupID     int    `json:"group_id"`
+	AuthType    string `json:"auth_type"`
+	Description string `json:"description"`
+	IPAddresses []struct {
+		ID      int    `json:"id"`
+		URL     string `json:"url"`
+		Display string `json:"display"`
+		Family  struct {
+			Value int    `json:"value"`
+			Label string `json:"label"`
+		} `json:"family"`
+		Address string `json:"address"`
+	} `json:"ip_addresses"`
+	Tags []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	} `json:"tags"`
+	Created     time.Time `json:"created"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+type NetboxFhrpGroupAssignment struct {
+	ID      int    `json:"id"`
+	URL     string `json:"url"`
+	Display string `json:"display"`
+	Group   struct {
+		ID       int    `json:"id"`
+		Protocol string `json:"protocol"`
+		GroupID  int    `json:"group_id"`
+	} `json:"group"`
+	InterfaceType string    `json:"interface_type"`
+	InterfaceID   int       `json:"interface_id"`
+	Priority      int       `json:"priority"`
+	Created       time.Time `json:"created"`
+	LastUpdated   time.Time `json:"last_updated"`
+}
+
+type NetboxFhrpGroupUpdateCreate struct {
+	Mode         string
+	FhrpGroupId  string
+	AssignmentId string
+	Protocol     string
+	GroupId      string
+	VirtualIPs   []string
+	Vrf          string
+	InterfaceId  string
+	Priority     string
+}
+
+type NetboxTunnel struct {
+	ID      int    `json:"id"`
+	URL     string `json:"url"`
+	Display string `json:"display"`
+	Name    string `json:"name"`
+	Status  struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	} `json:"status"`
+	Encapsulation struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	} `json:"encapsulation"`
+	Tenant struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"tenant"`
+	Description string `json:"description"`
+	Tags        []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	} `json:"tags"`
+	Created     time.Time `json:"created"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+type NetboxTunnelTermination struct {
+	ID      int    `json:"id"`
+	URL     string `json:"url"`
+	Display string `json:"display"`
+	Tunnel  struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"tunnel"`
+	Role struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	} `json:"role"`
+	TerminationType string `json:"termination_type"`
+	TerminationID   int    `json:"termination_id"`
+	OutsideIP       struct {
+		ID      int    `json:"id"`
+		Address string `json:"address"`
+	} `json:"outside_ip"`
+	Created     time.Time `json:"created"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+type NetboxTunnelUpdateCreate struct {
+	Mode            string
+	TunnelId        string
+	Name            string
+	Encapsulation   string
+	Description     string
+	InterfaceId     string
+	OutsideIPId     string
+	PeerInterfaceId string
+	PeerOutsideIPId string
+}
+
+type NetboxL2vpn struct {
+	ID         int    `json:"id"`
+	URL        string `json:"url"`
+	Display    string `json:"display"`
+	Identifier int    `json:"identifier"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+	Type       struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	} `json:"type"`
+	Description string        `json:"description"`
+	Tags        []interface{} `json:"tags"`
+	Created     time.Time     `json:"created"`
+	LastUpdated time.Time     `json:"last_updated"`
+}
@@ -0,0 +1,124 @@
+package netboxparser
+
+import (
+	"net/netip"
+	"strconv"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const defaultFhrpPriority = "100"
+
+// virtualIPAddress returns the virtual ip with the prefix length of the interface
+// address in the same network, or as host address when no network matches.
+func virtualIPAddress(virtualIP string, interfaceAddresses []string) string {
+	if prefix, err := netip.ParsePrefix(virtualIP); err == nil {
+		return prefix.String()
+	}
+	addr, err := netip.ParseAddr(virtualIP)
+	if err != nil {
+		return ""
+	}
+	for _, address := range interfaceAddresses {
+		prefix, err := netip.ParsePrefix(address)
+		if err == nil && prefix.Masked().Contains(addr) {
+			return netip.PrefixFrom(addr, prefix.Bits()).String()
+		}
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()).String()
+}
+
+// findFhrpGroup returns the NetBox group with the same protocol and group id that
+// has one of the virtual ips, group ids are reused between sites.
+func findFhrpGroup(netboxFhrpGroups *[]model.NetboxFhrpGroup, protocol string, groupId string, virtualIPs []string) *model.NetboxFhrpGroup {
+	for index, netboxFhrpGroup := range *netboxFhrpGroups {
+		if netboxFhrpGroup.Protocol != protocol || strconv.Itoa(netboxFhrpGroup.GroupID) != groupId {
+			continue
+		}
+		for _, ipAddress := range netboxFhrpGroup.IPAddresses {
+			for _, virtualIP := range virtualIPs {
+				if normalizeAddress(ipAddress.Address) == virtualIP {
+					return &(*netboxFhrpGroups)[index]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ParseFhrpGroups creates the FHRP groups with their virtual ips and assigns the
+// groups to the interfaces of the device with the configured priority. Assignments
+// of groups the device no longer has are removed, the groups themselves are kept.
+func ParseFhrpGroups(deviceInterfaces *[]model.FortigateInterface, netboxDeviceInterfaces *[]model.NetboxInterface, netboxFhrpGroups *[]model.NetboxFhrpGroup, netboxAssignments *[]model.NetboxFhrpGroupAssignment) []model.NetboxFhrpGroupUpdateCreate {
+	var results []model.NetboxFhrpGroupUpdateCreate
+	matchedIds := map[int]bool{}
+
+	for _, port := range *deviceInterfaces {
+		interfaceId := getParentID(port.Name, netboxDeviceInterfaces)
+		if interfaceId == "" {
+			continue
+		}
+
+		for _, group := range port.FhrpGroups {
+			var virtualIPs []string
+			for _, virtualIP := range group.VirtualIPs {
+				if address := virtualIPAddress(virtualIP, port.IPAddresses); address != "" {
+					virtualIPs = append(virtualIPs, address)
+				}
+			}
+			if len(virtualIPs) == 0 {
+				continue
+			}
+			priority := group.Priority
+			if priority == "" {
+				priority = defaultFhrpPriority
+			}
+
+			result := model.NetboxFhrpGroupUpdateCreate{
+				Mode:        "create",
+				Protocol:    group.Protocol,
+				GroupId:     group.GroupId,
+				VirtualIPs:  virtualIPs,
+				Vrf:         port.Vrf,
+				InterfaceId: interfaceId,
+				Priority:    priority,
+			}
+
+			existing := findFhrpGroup(netboxFhrpGroups, group.Protocol, group.GroupId, virtualIPs)
+			if existing == nil {
+				results = append(results, result)
+				continue
+			}
+			result.FhrpGroupId = strconv.Itoa(existing.ID)
+
+			var assignment *model.NetboxFhrpGroupAssignment
+			for index, netboxAssignment := range *netboxAssignments {
+				if netboxAssignment.Group.ID == existing.ID && strconv.Itoa(netboxAssignment.InterfaceID) == interfaceId {
+					assignment = &(*netboxAssignments)[index]
+					matchedIds[netboxAssignment.ID] = true
+				}
+			}
+			switch {
+			case assignment == nil:
+				results = append(results, result)
+			case strconv.Itoa(assignment.Priority) != priority:
+				result.Mode = "update"
+				result.AssignmentId = strconv.Itoa(assignment.ID)
+				results = append(results, result)
+			}
+		}
+	}
+
+	for _, netboxAssignment := range *netboxAssignments {
+		if !matchedIds[netboxAssignment.ID] {
+			results = append(results, model.NetboxFhrpGroupUpdateCreate{
+				Mode:         "delete",
+				AssignmentId: strconv.Itoa(netboxAssignment.ID),
+				Protocol:     netboxAssignment.Group.Protocol,
+				GroupId:      strconv.Itoa(netboxAssignment.Group.GroupID),
+			})
+		}
+	}
+
+	return results
+}
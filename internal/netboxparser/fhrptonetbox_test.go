@@ -0,0 +1,100 @@
+package netboxparser
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+func TestVirtualIPAddress(t *testing.T) {
+	tests := []struct {
+		virtualIP string
+		addresses []string
+		want      string
+	}{
+		{"10.0.0.254", []string{"192.0.2.1/24", "10.0.0.1/24"}, "10.0.0.254/24"},
+		{"10.0.0.254", nil, "10.0.0.254/32"},
+		{"2001:db8::ffff", []string{"2001:db8::1/64"}, "2001:db8::ffff/64"},
+		{"10.0.0.254/25", []string{"10.0.0.1/24"}, "10.0.0.254/25"},
+		{"invalid", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := virtualIPAddress(tt.virtualIP, tt.addresses); got != tt.want {
+			t.Errorf("virtualIPAddress(%q, %v) = %q, want %q", tt.virtualIP, tt.addresses, got, tt.want)
+		}
+	}
+}
+
+func TestParseFhrpGroups(t *testing.T) {
+	netboxInterfaces := unmarshalNetbox[[]model.NetboxInterface](t, testNetboxInterfaces)
+	netboxFhrpGroups := unmarshalNetbox[[]model.NetboxFhrpGroup](t, `[
+		{"id": 1, "protocol": "vrrp2", "group_id": 10, "ip_addresses": [{"address": "10.0.0.254/24"}]},
+		{"id": 2, "protocol": "vrrp2", "group_id": 10, "ip_addresses": [{"address": "10.1.0.254/24"}]},
+		{"id": 3, "protocol": "hsrp", "group_id": 20, "ip_addresses": [{"address": "10.2.0.254/24"}]}
+	]`)
+	vrrp := model.FhrpGroup{Protocol: "vrrp2", GroupId: "10", VirtualIPs: []string{"10.0.0.254"}, Priority: "110"}
+
+	tests := []struct {
+		name        string
+		interfaces  []model.FortigateInterface
+		assignments string
+		want        []model.NetboxFhrpGroupUpdateCreate
+	}{
+		{
+			name:        "create group and assignment",
+			interfaces:  []model.FortigateInterface{{Name: "port1", Vrf: "RED", IPAddresses: []string{"10.5.0.1/24"}, FhrpGroups: []model.FhrpGroup{{Protocol: "vrrp3", GroupId: "5", VirtualIPs: []string{"10.5.0.254"}}}}},
+			assignments: `[]`,
+			want:        []model.NetboxFhrpGroupUpdateCreate{{Mode: "create", Protocol: "vrrp3", GroupId: "5", VirtualIPs: []string{"10.5.0.254/24"}, Vrf: "RED", InterfaceId: "10", Priority: "100"}},
+		},
+		{
+			name:        "assign existing group with the same virtual ip",
+			interfaces:  []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"10.0.0.1/24"}, FhrpGroups: []model.FhrpGroup{vrrp}}},
+			assignments: `[]`,
+			want:        []model.NetboxFhrpGroupUpdateCreate{{Mode: "create", FhrpGroupId: "1", Protocol: "vrrp2", GroupId: "10", VirtualIPs: []string{"10.0.0.254/24"}, InterfaceId: "10", Priority: "110"}},
+		},
+		{
+			name:        "unchanged assignment",
+			interfaces:  []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"10.0.0.1/24"}, FhrpGroups: []model.FhrpGroup{vrrp}}},
+			assignments: `[{"id": 7, "group": {"id": 1, "protocol": "vrrp2", "group_id": 10}, "interface_id": 10, "priority": 110}]`,
+		},
+		{
+			name:        "priority changed",
+			interfaces:  []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"10.0.0.1/24"}, FhrpGroups: []model.FhrpGroup{vrrp}}},
+			assignments: `[{"id": 7, "group": {"id": 1, "protocol": "vrrp2", "group_id": 10}, "interface_id": 10, "priority": 100}]`,
+			want:        []model.NetboxFhrpGroupUpdateCreate{{Mode: "update", FhrpGroupId: "1", AssignmentId: "7", Protocol: "vrrp2", GroupId: "10", VirtualIPs: []string{"10.0.0.254/24"}, InterfaceId: "10", Priority: "110"}},
+		},
+		{
+			name:       "stale assignments are deleted",
+			interfaces: []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"10.0.0.1/24"}, FhrpGroups: []model.FhrpGroup{vrrp}}},
+			assignments: `[
+				{"id": 7, "group": {"id": 1, "protocol": "vrrp2", "group_id": 10}, "interface_id": 10, "priority": 110},
+				{"id": 8, "group": {"id": 1, "protocol": "vrrp2", "group_id": 10}, "interface_id": 20, "priority": 110},
+				{"id": 9, "group": {"id": 3, "protocol": "hsrp", "group_id": 20}, "interface_id": 10, "priority": 100}
+			]`,
+			want: []model.NetboxFhrpGroupUpdateCreate{
+				{Mode: "delete", AssignmentId: "8", Protocol: "vrrp2", GroupId: "10"},
+				{Mode: "delete", AssignmentId: "9", Protocol: "hsrp", GroupId: "20"},
+			},
+		},
+		{
+			name:        "interface missing in netbox",
+			interfaces:  []model.FortigateInterface{{Name: "port3", IPAddresses: []string{"10.0.0.1/24"}, FhrpGroups: []model.FhrpGroup{vrrp}}},
+			assignments: `[]`,
+		},
+	}
+
+	for _, tt := range tests {
+		netboxAssignments := unmarshalNetbox[[]model.NetboxFhrpGroupAssignment](t, tt.assignments)
+		got := ParseFhrpGroups(&tt.interfaces, &netboxInterfaces, &netboxFhrpGroups, &netboxAssignments)
+		if !slices.EqualFunc(got, tt.want, equalFhrpGroupUpdate) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func equalFhrpGroupUpdate(a model.NetboxFhrpGroupUpdateCreate, b model.NetboxFhrpGroupUpdateCreate) bool {
+	return a.Mode == b.Mode && a.FhrpGroupId == b.FhrpGroupId && a.AssignmentId == b.AssignmentId &&
+		a.Protocol == b.Protocol && a.GroupId == b.GroupId && slices.Equal(a.VirtualIPs, b.VirtualIPs) &&
+		a.Vrf == b.Vrf && a.InterfaceId == b.InterfaceId && a.Priority == b.Priority
+}
@@ -1,6 +1,7 @@
 package netboxparser
 
 import (
+	"slices"
 	"strconv"
 	"strings"
 
@@ -21,7 +22,48 @@ func getParentID(parentName string, netboxDeviceInterfaces *[]model.NetboxInterf
 	return ""
 }
 
-func processPort(port model.FortigateInterface, allMembers map[string]int, fortiInterfaces *[]model.FortigateInterface, netboxDeviceInterfaces *[]model.NetboxInterface, deviceId string) model.NetboxInterfaceUpdateCreate {
+func sameTaggedVlans(vlanIds []string, netboxInterface *model.NetboxInterface) bool {
+	if len(vlanIds) != len(netboxInterface.TaggedVlans) {
+		return false
+	}
+	for _, taggedVlan := range netboxInterface.TaggedVlans {
+		if !slices.Contains(vlanIds, strconv.Itoa(taggedVlan.Vid)) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameVdc checks if the interface is only assigned to the virtual device context of the given vdom.
+func sameVdc(vdom string, netboxInterface *model.NetboxInterface) bool {
+	return len(netboxInterface.Vdcs) == 1 && strings.EqualFold(netboxInterface.Vdcs[0].Name, vdom)
+}
+
+// samePrefixedTag checks if the interface only has the tag with the prefix for the given
+// value, like the zone tag of a zone, or none when there is no value.
+func samePrefixedTag(prefix string, value string, netboxInterface *model.NetboxInterface) bool {
+	var prefixedTags []string
+	for _, tag := range netboxInterface.Tags {
+		if strings.HasPrefix(tag.Name, prefix) {
+			prefixedTags = append(prefixedTags, tag.Name)
+		}
+	}
+	if value == "" {
+		return len(prefixedTags) == 0
+	}
+	return len(prefixedTags) == 1 && prefixedTags[0] == prefix+value
+}
+
+// sdwanPriority returns the SD-WAN priority custom field of the interface, or an empty
+// string when it is not set.
+func sdwanPriority(netboxInterface *model.NetboxInterface) string {
+	if netboxInterface.CustomFields.SdwanPriority == nil {
+		return ""
+	}
+	return strconv.Itoa(*netboxInterface.CustomFields.SdwanPriority)
+}
+
+func processPort(port model.FortigateInterface, allMembers map[string]int, fortiInterfaces *[]model.FortigateInterface, netboxDeviceInterfaces *[]model.NetboxInterface, deviceId string, typeTable []interfaceTypePattern) model.NetboxInterfaceUpdateCreate {
 	var matched model.NetboxInterfaceUpdateCreate
 	for _, netboxInterface := range *netboxDeviceInterfaces {
 
@@ -34,8 +76,29 @@ func processPort(port model.FortigateInterface, allMembers map[string]int, forti
 				Matched : true,
 			}
 
+			zoneChanged := !samePrefixedTag(model.ZoneTagPrefix, port.Zone, &netboxInterface)
+			if zoneChanged {
+				matched.Zone = &port.Zone
+			}
+			sdwanChanged := !samePrefixedTag(model.SdwanTagPrefix, port.SdwanZone, &netboxInterface)
+			if sdwanChanged {
+				matched.SdwanZone = &port.SdwanZone
+			}
+			if port.SdwanGateway != netboxInterface.CustomFields.SdwanGateway {
+				matched.SdwanGateway = &port.SdwanGateway
+			}
+			if port.SdwanPriority != sdwanPriority(&netboxInterface) {
+				matched.SdwanPriority = &port.SdwanPriority
+			}
+
 			if len(netboxInterface.Tags) != 0 {
 				for _, tag := range netboxInterface.Tags {
+					if zoneChanged && strings.HasPrefix(tag.Name, model.ZoneTagPrefix) {
+						continue
+					}
+					if sdwanChanged && strings.HasPrefix(tag.Name, model.SdwanTagPrefix) {
+						continue
+					}
 					matched.Tags = append(matched.Tags,  strconv.Itoa(tag.ID))
 				}
 			}
@@ -49,6 +112,19 @@ func processPort(port model.FortigateInterface, allMembers map[string]int, forti
 			if !strings.EqualFold(port.Description, netboxInterface.Description) {
 				matched.Description = port.Description
 			}
+			if port.InterfaceType == "physical" {
+				portType, override := physicalInterfaceType(port.Name, port.Speed, typeTable)
+				if portType != "" && portType != netboxInterface.Type.Value && (override || typeSpeeds[portType] != typeSpeeds[netboxInterface.Type.Value]) {
+					matched.PortTypeUpdate = portType
+				}
+				speed, duplex := parseSpeed(port.Speed)
+				if speed != 0 && (netboxInterface.Speed == nil || *netboxInterface.Speed != speed) {
+					matched.Speed = speed
+				}
+				if duplex != "" && duplex != netboxInterface.Duplex.Value {
+					matched.Duplex = duplex
+				}
+			}
 			if port.InterfaceType == "physical" && len(allMembers) > 0 {
 				if parentIndex, ok := allMembers[port.Name]; ok {
 					if (*fortiInterfaces)[parentIndex].InterfaceType == lagName {
@@ -95,6 +171,40 @@ func processPort(port model.FortigateInterface, allMembers map[string]int, forti
 					matched.VlanId = port.VlanId
 				}
 			}
+			if port.InterfaceType == "virtual" {
+				if port.Parent != "" {
+					matched.ParentId = getParentID(port.Parent, netboxDeviceInterfaces)
+					if matched.ParentId != strconv.Itoa(netboxInterface.Parent.ID) {
+						matched.Parent = port.Parent
+					}
+				}
+				if netboxInterface.Type.Value != "virtual" {
+					matched.PortTypeUpdate = "virtual"
+				}
+			}
+			if port.VlanMode != "" && port.InterfaceType != "vlan" {
+				if netboxInterface.Mode.Value != port.VlanMode {
+					matched.VlanMode = port.VlanMode
+				}
+				if port.VlanId != "" && port.VlanId != strconv.Itoa(netboxInterface.UntaggedVlan.Vid) {
+					matched.VlanId = port.VlanId
+				}
+				if port.VlanMode == "tagged" && !sameTaggedVlans(port.TaggedVlans, &netboxInterface) {
+					matched.TaggedVlans = port.TaggedVlans
+				}
+			}
+			if port.Vrf != "" && !strings.EqualFold(port.Vrf, netboxInterface.Vrf.Name) {
+				matched.Vrf = port.Vrf
+			}
+			if port.Vdom != "" && !sameVdc(port.Vdom, &netboxInterface) {
+				matched.Vdc = port.Vdom
+			}
+			if port.Label != "" && port.Label != netboxInterface.Label {
+				matched.Label = port.Label
+			}
+			if port.MgmtOnly != nil && *port.MgmtOnly != netboxInterface.MgmtOnly {
+				matched.MgmtOnly = port.MgmtOnly
+			}
 			if port.Status != "" {
 				if port.Status == "down" && netboxInterface.Enabled {
 					matched.Status = "disabled"
@@ -113,6 +223,9 @@ func processPort(port model.FortigateInterface, allMembers map[string]int, forti
 			matched.DeviceId = deviceId
 			matched.Description = port.Description
 			matched.PortType = port.InterfaceType
+			matched.VlanMode = port.VlanMode
+			matched.VlanId = port.VlanId
+			matched.TaggedVlans = port.TaggedVlans
 			if port.Status != "" {
 				if port.Status == "down" {
 					matched.Status = "disabled"
@@ -128,6 +241,9 @@ func processPort(port model.FortigateInterface, allMembers map[string]int, forti
 				matched.DeviceId = deviceId
 				matched.Description = port.Description
 				matched.Status = port.Status
+				matched.VlanMode = port.VlanMode
+				matched.VlanId = port.VlanId
+				matched.TaggedVlans = port.TaggedVlans
 				if port.Status != "" {
 					if port.Status == "down" {
 						matched.Status = "disabled"
@@ -152,9 +268,50 @@ func processPort(port model.FortigateInterface, allMembers map[string]int, forti
 			matched.Description = port.Description
 			matched.PortType = port.InterfaceType
 			matched.DeviceId = deviceId
+			matched.VlanMode = port.VlanMode
+			matched.VlanId = port.VlanId
+			if port.Status == "down" {
+				matched.Status = "disabled"
+			}
+		} else if port.InterfaceType == "virtual" {
+			matched.Mode = "create"
+			matched.Name = port.Name
+			matched.Description = port.Description
+			matched.PortType = port.InterfaceType
+			matched.DeviceId = deviceId
+			matched.Parent = port.Parent
+			matched.ParentId = getParentID(matched.Parent, netboxDeviceInterfaces)
+			if port.Status == "down" {
+				matched.Status = "disabled"
+			}
+		}
+		if matched.Mode == "create" {
+			if port.InterfaceType == "physical" {
+				matched.PortTypeUpdate, _ = physicalInterfaceType(port.Name, port.Speed, typeTable)
+				if matched.PortTypeUpdate == "" {
+					matched.PortTypeUpdate = defaultPhysicalType
+				}
+				matched.Speed, matched.Duplex = parseSpeed(port.Speed)
+			}
+			matched.Vrf = port.Vrf
+			matched.Label = port.Label
+			matched.MgmtOnly = port.MgmtOnly
+			matched.Vdc = port.Vdom
+			if port.Zone != "" {
+				matched.Zone = &port.Zone
+			}
+			if port.SdwanZone != "" {
+				matched.SdwanZone = &port.SdwanZone
+			}
+			if port.SdwanGateway != "" {
+				matched.SdwanGateway = &port.SdwanGateway
+			}
+			if port.SdwanPriority != "" {
+				matched.SdwanPriority = &port.SdwanPriority
+			}
 		}
 	} else {
-		if matched.Description != "" || matched.Status != "" || matched.PortTypeUpdate != "" || matched.Parent != "" || matched.VlanMode != "" {
+		if matched.Description != "" || matched.Status != "" || matched.PortTypeUpdate != "" || matched.Parent != "" || matched.VlanMode != "" || matched.VlanId != "" || matched.TaggedVlans != nil || matched.Vrf != "" || matched.Vdc != "" || matched.Label != "" || matched.MgmtOnly != nil || matched.Zone != nil || matched.SdwanZone != nil || matched.SdwanGateway != nil || matched.SdwanPriority != nil || matched.Speed != 0 || matched.Duplex != "" {
 			if !strings.HasPrefix(port.Parent, "npu") {
 				matched.Mode = "update"
 			}			
@@ -163,8 +320,9 @@ func processPort(port model.FortigateInterface, allMembers map[string]int, forti
 	return matched
 }
 
-func ParseFortigateInterfaces(fortiInterfaces *[]model.FortigateInterface, netboxDeviceInterfaces *[]model.NetboxInterface, deviceId string) []model.NetboxInterfaceUpdateCreate {
+func ParseFortigateInterfaces(fortiInterfaces *[]model.FortigateInterface, netboxDeviceInterfaces *[]model.NetboxInterface, deviceId string, platform string, typeOverrides map[string]string) []model.NetboxInterfaceUpdateCreate {
 	var results []model.NetboxInterfaceUpdateCreate
+	typeTable := newInterfaceTypeTable(platform, typeOverrides)
 
 	allMembers := make(map[string]int)
 	for i, aggPort := range *fortiInterfaces {
@@ -174,7 +332,7 @@ func ParseFortigateInterfaces(fortiInterfaces *[]model.FortigateInterface, netbo
 	}
 
 	for _, port := range *fortiInterfaces {
-		result := processPort(port, allMembers, fortiInterfaces, netboxDeviceInterfaces, deviceId)
+		result := processPort(port, allMembers, fortiInterfaces, netboxDeviceInterfaces, deviceId, typeTable)
 		if result.Mode != "" {
 			results = append(results, result)
 		}
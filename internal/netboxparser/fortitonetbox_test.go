@@ -0,0 +1,116 @@
+package netboxparser
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+// unmarshalNetbox decodes a NetBox api response written as json in a test.
+func unmarshalNetbox[T any](t *testing.T, data string) T {
+	t.Helper()
+	var result T
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestProcessPortTags(t *testing.T) {
+	netboxInterfaces := unmarshalNetbox[[]model.NetboxInterface](t, `[{
+		"id": 10, "name": "port1", "enabled": true, "type": {"value": "1000base-t"},
+		"tags": [
+			{"id": 1, "name": "oxidized-sync"},
+			{"id": 2, "name": "zone:lan"},
+			{"id": 3, "name": "sdwan:virtual-wan-link"}
+		]
+	}]`)
+
+	tests := []struct {
+		name      string
+		zone      string
+		sdwanZone string
+		mode      string
+		tags      []string
+		zoneTag   *string
+		sdwanTag  *string
+	}{
+		{"unchanged", "lan", "virtual-wan-link", "", []string{"1", "2", "3"}, nil, nil},
+		{"zone changed", "dmz", "virtual-wan-link", "update", []string{"1", "3"}, ptr("dmz"), nil},
+		{"zone removed", "", "virtual-wan-link", "update", []string{"1", "3"}, ptr(""), nil},
+		{"sdwan zone changed", "lan", "internet", "update", []string{"1", "2"}, nil, ptr("internet")},
+		{"both removed", "", "", "update", []string{"1"}, ptr(""), ptr("")},
+	}
+	for _, tt := range tests {
+		port := model.FortigateInterface{Name: "port1", InterfaceType: "physical", Zone: tt.zone, SdwanZone: tt.sdwanZone}
+		result := processPort(port, nil, &[]model.FortigateInterface{port}, &netboxInterfaces, "1", nil)
+		if result.Mode != tt.mode || !slices.Equal(result.Tags, tt.tags) {
+			t.Errorf("%s: got mode %q tags %v, want %q %v", tt.name, result.Mode, result.Tags, tt.mode, tt.tags)
+		}
+		if !equalPtr(result.Zone, tt.zoneTag) || !equalPtr(result.SdwanZone, tt.sdwanTag) {
+			t.Errorf("%s: got zone %v sdwan zone %v, want %v %v", tt.name, deref(result.Zone), deref(result.SdwanZone), deref(tt.zoneTag), deref(tt.sdwanTag))
+		}
+	}
+}
+
+func TestProcessPortCreateWithZone(t *testing.T) {
+	port := model.FortigateInterface{Name: "port2", InterfaceType: "physical", Zone: "dmz"}
+	result := processPort(port, nil, &[]model.FortigateInterface{port}, &[]model.NetboxInterface{}, "1", nil)
+	if result.Mode != "create" || !equalPtr(result.Zone, ptr("dmz")) || result.SdwanZone != nil {
+		t.Errorf("got mode %q zone %v sdwan zone %v, want create dmz <nil>", result.Mode, deref(result.Zone), deref(result.SdwanZone))
+	}
+}
+
+func TestProcessPortSdwanCustomFields(t *testing.T) {
+	netboxInterfaces := unmarshalNetbox[[]model.NetboxInterface](t, `[{
+		"id": 10, "name": "port1", "enabled": true, "type": {"value": "1000base-t"},
+		"custom_fields": {"sdwan_gateway": "203.0.113.1", "sdwan_priority": 10}
+	}]`)
+
+	tests := []struct {
+		name     string
+		gateway  string
+		priority string
+		mode     string
+		wantGw   *string
+		wantPrio *string
+	}{
+		{"unchanged", "203.0.113.1", "10", "", nil, nil},
+		{"gateway changed", "198.51.100.1", "10", "update", ptr("198.51.100.1"), nil},
+		{"priority changed", "203.0.113.1", "20", "update", nil, ptr("20")},
+		{"no longer a member", "", "", "update", ptr(""), ptr("")},
+	}
+	for _, tt := range tests {
+		port := model.FortigateInterface{Name: "port1", InterfaceType: "physical", SdwanGateway: tt.gateway, SdwanPriority: tt.priority}
+		result := processPort(port, nil, &[]model.FortigateInterface{port}, &netboxInterfaces, "1", nil)
+		if result.Mode != tt.mode || !equalPtr(result.SdwanGateway, tt.wantGw) || !equalPtr(result.SdwanPriority, tt.wantPrio) {
+			t.Errorf("%s: got mode %q gateway %v priority %v, want %q %v %v", tt.name, result.Mode, deref(result.SdwanGateway), deref(result.SdwanPriority), tt.mode, deref(tt.wantGw), deref(tt.wantPrio))
+		}
+	}
+
+	port := model.FortigateInterface{Name: "port2", InterfaceType: "physical", SdwanGateway: "203.0.113.1", SdwanPriority: "10"}
+	result := processPort(port, nil, &[]model.FortigateInterface{port}, &[]model.NetboxInterface{}, "1", nil)
+	if result.Mode != "create" || !equalPtr(result.SdwanGateway, ptr("203.0.113.1")) || !equalPtr(result.SdwanPriority, ptr("10")) {
+		t.Errorf("create: got mode %q gateway %v priority %v", result.Mode, deref(result.SdwanGateway), deref(result.SdwanPriority))
+	}
+}
+
+func ptr(value string) *string {
+	return &value
+}
+
+func deref(value *string) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func equalPtr(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
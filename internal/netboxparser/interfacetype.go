@@ -0,0 +1,174 @@
+package netboxparser
+
+import (
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultPhysicalType = "1000base-t"
+
+type interfaceTypePattern struct {
+	pattern       *regexp.Regexp
+	interfaceType string
+	override      bool
+}
+
+type namePattern struct {
+	pattern       string
+	interfaceType string
+}
+
+// platformInterfaceTypes are the NetBox types of interface names that only exist for
+// one kind of port, per Oxidized model. The first matching pattern is used.
+var platformInterfaceTypes = map[string][]namePattern{
+	"FortiOS": {
+		{`^x\d+$`, "10gbase-x-sfpp"},
+		{`^s\d+$`, "1000base-x-sfp"},
+	},
+	"IOS":   ciscoInterfaceTypes,
+	"IOSXE": ciscoInterfaceTypes,
+	"JunOS": {
+		{`^ge-`, "1000base-t"},
+		{`^xe-`, "10gbase-x-sfpp"},
+	},
+	"VRP": {
+		{`^XGigabitEthernet`, "10gbase-x-sfpp"},
+		{`^GigabitEthernet`, "1000base-t"},
+		{`^10GE`, "10gbase-x-sfpp"},
+		{`^25GE`, "25gbase-x-sfp28"},
+		{`^40GE`, "40gbase-x-qsfpp"},
+		{`^100GE`, "100gbase-x-qsfp28"},
+	},
+	"Comware": {
+		{`^Ten-GigabitEthernet`, "10gbase-x-sfpp"},
+		{`^Twenty-FiveGigE`, "25gbase-x-sfp28"},
+		{`^FortyGigE`, "40gbase-x-qsfpp"},
+		{`^HundredGigE`, "100gbase-x-qsfp28"},
+		{`^GigabitEthernet`, "1000base-t"},
+	},
+}
+
+var ciscoInterfaceTypes = []namePattern{
+	{`^HundredGigE`, "100gbase-x-qsfp28"},
+	{`^FortyGigabitEthernet`, "40gbase-x-qsfpp"},
+	{`^TwentyFiveGigE`, "25gbase-x-sfp28"},
+	{`^TenGigabitEthernet`, "10gbase-x-sfpp"},
+	{`^FiveGigabitEthernet`, "5gbase-t"},
+	{`^TwoGigabitEthernet`, "2.5gbase-t"},
+	{`^GigabitEthernet`, "1000base-t"},
+	{`^FastEthernet`, "100base-tx"},
+}
+
+// speedInterfaceTypes are the NetBox types for a port speed in Mbps.
+var speedInterfaceTypes = map[int]string{
+	10:     "10base-t",
+	100:    "100base-tx",
+	1000:   "1000base-t",
+	2500:   "2.5gbase-t",
+	5000:   "5gbase-t",
+	10000:  "10gbase-x-sfpp",
+	25000:  "25gbase-x-sfp28",
+	40000:  "40gbase-x-qsfpp",
+	50000:  "50gbase-x-sfp56",
+	100000: "100gbase-x-qsfp28",
+	200000: "200gbase-x-qsfp56",
+	400000: "400gbase-x-qsfpdd",
+}
+
+// typeSpeeds is the speed in Mbps of the common NetBox types, a type is only replaced
+// by an inferred type of another speed so a more specific type like 1000base-x-sfp
+// is kept.
+var typeSpeeds = map[string]int{
+	"10base-t":          10,
+	"100base-tx":        100,
+	"100base-fx":        100,
+	"1000base-t":        1000,
+	"1000base-x-gbic":   1000,
+	"1000base-x-sfp":    1000,
+	"2.5gbase-t":        2500,
+	"5gbase-t":          5000,
+	"10gbase-t":         10000,
+	"10gbase-cx4":       10000,
+	"10gbase-x-sfpp":    10000,
+	"10gbase-x-xfp":     10000,
+	"25gbase-t":         25000,
+	"25gbase-x-sfp28":   25000,
+	"40gbase-x-qsfpp":   40000,
+	"50gbase-x-sfp56":   50000,
+	"50gbase-x-sfp28":   50000,
+	"100gbase-x-cfp":    100000,
+	"100gbase-x-qsfp28": 100000,
+	"100gbase-x-dsfp":   100000,
+	"200gbase-x-qsfp56": 200000,
+	"400gbase-x-qsfpdd": 400000,
+	"400gbase-x-osfp":   400000,
+}
+
+// newInterfaceTypeTable returns the name patterns for the platform, the configured
+// overrides are checked before the built-in patterns.
+func newInterfaceTypeTable(platform string, overrides map[string]string) []interfaceTypePattern {
+	var result []interfaceTypePattern
+
+	patterns := make([]string, 0, len(overrides))
+	for pattern := range overrides {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		interfaceType := overrides[pattern]
+		compiled, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			slog.Warn("Invalid interface type pattern", "pattern", pattern, "error", err)
+			continue
+		}
+		result = append(result, interfaceTypePattern{compiled, interfaceType, true})
+	}
+	for _, namePattern := range platformInterfaceTypes[platform] {
+		result = append(result, interfaceTypePattern{regexp.MustCompile("(?i)" + namePattern.pattern), namePattern.interfaceType, false})
+	}
+
+	return result
+}
+
+// parseSpeed returns the speed in Kbps and the duplex of speed settings like "1000",
+// "10000full", "1000auto", "25gfull" or "100Gfull". An auto speed returns 0.
+func parseSpeed(speed string) (int, string) {
+	speed = strings.ToLower(strings.TrimSpace(speed))
+
+	var duplex string
+	for _, suffix := range []string{"full", "half", "auto"} {
+		if strings.HasSuffix(speed, suffix) {
+			duplex = suffix
+			speed = strings.TrimSuffix(speed, suffix)
+			break
+		}
+	}
+
+	multiplier := 1000
+	if strings.HasSuffix(speed, "g") {
+		multiplier = 1000000
+		speed = strings.TrimSuffix(speed, "g")
+	}
+	mbps, err := strconv.Atoi(speed)
+	if err != nil || mbps <= 0 {
+		return 0, duplex
+	}
+	return mbps * multiplier, duplex
+}
+
+// physicalInterfaceType returns the NetBox type of a physical port from the name patterns
+// or else the speed, the bool is true when the type comes from a configured override.
+func physicalInterfaceType(name string, speed string, typeTable []interfaceTypePattern) (string, bool) {
+	for _, entry := range typeTable {
+		if entry.pattern.MatchString(name) {
+			return entry.interfaceType, entry.override
+		}
+	}
+
+	kbps, _ := parseSpeed(speed)
+	return speedInterfaceTypes[kbps/1000], false
+}
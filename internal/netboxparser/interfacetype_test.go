@@ -0,0 +1,67 @@
+package netboxparser
+
+import "testing"
+
+func TestParseSpeed(t *testing.T) {
+	tests := []struct {
+		speed  string
+		kbps   int
+		duplex string
+	}{
+		{"", 0, ""},
+		{"auto", 0, "auto"},
+		{"1000", 1000000, ""},
+		{"1000auto", 1000000, "auto"},
+		{"100half", 100000, "half"},
+		{"10000full", 10000000, "full"},
+		{"25gfull", 25000000, "full"},
+		{" 100Gfull ", 100000000, "full"},
+		{"40000", 40000000, ""},
+		{"fast", 0, ""},
+		{"-1full", 0, "full"},
+	}
+	for _, tt := range tests {
+		kbps, duplex := parseSpeed(tt.speed)
+		if kbps != tt.kbps || duplex != tt.duplex {
+			t.Errorf("parseSpeed(%q) = %d, %q, want %d, %q", tt.speed, kbps, duplex, tt.kbps, tt.duplex)
+		}
+	}
+}
+
+func TestPhysicalInterfaceType(t *testing.T) {
+	tests := []struct {
+		platform      string
+		overrides     map[string]string
+		name          string
+		speed         string
+		interfaceType string
+		override      bool
+	}{
+		{"FortiOS", nil, "x1", "", "10gbase-x-sfpp", false},
+		{"FortiOS", nil, "s2", "auto", "1000base-x-sfp", false},
+		{"FortiOS", nil, "port1", "1000full", "1000base-t", false},
+		{"FortiOS", nil, "port25", "10000full", "10gbase-x-sfpp", false},
+		{"FortiOS", nil, "port27", "25gfull", "25gbase-x-sfp28", false},
+		{"FortiOS", nil, "port29", "40000full", "40gbase-x-qsfpp", false},
+		{"FortiOS", nil, "port1", "auto", "", false},
+		{"FortiOS", nil, "port1", "12345full", "", false},
+		{"FortiOS", map[string]string{"^port(25|26)$": "10gbase-t"}, "PORT25", "10000full", "10gbase-t", true},
+		{"FortiOS", map[string]string{"^port(25|26)$": "10gbase-t"}, "port27", "", "", false},
+		{"FortiOS", map[string]string{"^x": "25gbase-x-sfp28"}, "x1", "", "25gbase-x-sfp28", true},
+		{"FortiOS", map[string]string{"[": "10gbase-t", "^x": "25gbase-x-sfp28"}, "x1", "", "25gbase-x-sfp28", true},
+		{"IOS", nil, "TenGigabitEthernet1/0/1", "", "10gbase-x-sfpp", false},
+		{"IOSXE", nil, "GigabitEthernet0/0/0", "", "1000base-t", false},
+		{"JunOS", nil, "xe-0/0/0", "", "10gbase-x-sfpp", false},
+		{"VRP", nil, "XGigabitEthernet0/0/1", "", "10gbase-x-sfpp", false},
+		{"Comware", nil, "Ten-GigabitEthernet1/0/49", "", "10gbase-x-sfpp", false},
+		{"SONiC", nil, "Ethernet0", "100000", "100gbase-x-qsfp28", false},
+		{"", nil, "x1", "", "", false},
+	}
+	for _, tt := range tests {
+		typeTable := newInterfaceTypeTable(tt.platform, tt.overrides)
+		interfaceType, override := physicalInterfaceType(tt.name, tt.speed, typeTable)
+		if interfaceType != tt.interfaceType || override != tt.override {
+			t.Errorf("physicalInterfaceType(%s %q, %q) = %q, %v, want %q, %v", tt.platform, tt.name, tt.speed, interfaceType, override, tt.interfaceType, tt.override)
+		}
+	}
+}
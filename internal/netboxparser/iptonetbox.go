@@ -0,0 +1,111 @@
+package netboxparser
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const standbyDescription = "standby"
+
+type deviceIPAddress struct {
+	address     string
+	interfaceId string
+	vrf         string
+	description string
+}
+
+// normalizeAddress returns the address in the notation NetBox uses, so "2001:DB8::1/64"
+// and "2001:db8::1/64" are the same address.
+func normalizeAddress(address string) string {
+	prefix, err := netip.ParsePrefix(address)
+	if err != nil {
+		return address
+	}
+	return prefix.String()
+}
+
+func hasTag(ipAddress *model.NetboxIPAddress, tagId int) bool {
+	for _, tag := range ipAddress.Tags {
+		if tag.ID == tagId {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIPAddresses compares the addresses of the device interfaces with the addresses
+// assigned to the interfaces of the NetBox device. Addresses that are no longer
+// configured are only removed when they have the managed tag.
+func ParseIPAddresses(deviceInterfaces *[]model.FortigateInterface, netboxDeviceInterfaces *[]model.NetboxInterface, netboxIPAddresses *[]model.NetboxIPAddress, managedTagId int) []model.NetboxIPAddressUpdateCreate {
+	var results []model.NetboxIPAddressUpdateCreate
+
+	var wanted []deviceIPAddress
+	for _, port := range *deviceInterfaces {
+		interfaceId := getParentID(port.Name, netboxDeviceInterfaces)
+		if interfaceId == "" {
+			continue
+		}
+		for _, address := range port.IPAddresses {
+			wanted = append(wanted, deviceIPAddress{normalizeAddress(address), interfaceId, port.Vrf, ""})
+		}
+		for _, address := range port.StandbyIPs {
+			wanted = append(wanted, deviceIPAddress{normalizeAddress(address), interfaceId, port.Vrf, standbyDescription})
+		}
+	}
+
+	matchedIds := map[int]bool{}
+	for _, ip := range wanted {
+		var existing *model.NetboxIPAddress
+		for index, netboxIPAddress := range *netboxIPAddresses {
+			if !matchedIds[netboxIPAddress.ID] && normalizeAddress(netboxIPAddress.Address) == ip.address {
+				existing = &(*netboxIPAddresses)[index]
+				break
+			}
+		}
+
+		if existing == nil {
+			results = append(results, model.NetboxIPAddressUpdateCreate{
+				Mode:        "create",
+				Address:     ip.address,
+				InterfaceId: ip.interfaceId,
+				Vrf:         ip.vrf,
+				Description: ip.description,
+			})
+			continue
+		}
+
+		matchedIds[existing.ID] = true
+		update := model.NetboxIPAddressUpdateCreate{
+			IPAddressId: strconv.Itoa(existing.ID),
+			Address:     ip.address,
+		}
+		if strconv.Itoa(existing.AssignedObjectID) != ip.interfaceId {
+			update.InterfaceId = ip.interfaceId
+		}
+		if ip.vrf != "" && !strings.EqualFold(ip.vrf, existing.Vrf.Name) {
+			update.Vrf = ip.vrf
+		}
+		if ip.description != "" && ip.description != existing.Description {
+			update.Description = ip.description
+		}
+		if update.InterfaceId != "" || update.Vrf != "" || update.Description != "" {
+			update.Mode = "update"
+			results = append(results, update)
+		}
+	}
+
+	for _, netboxIPAddress := range *netboxIPAddresses {
+		if !matchedIds[netboxIPAddress.ID] && hasTag(&netboxIPAddress, managedTagId) {
+			results = append(results, model.NetboxIPAddressUpdateCreate{
+				Mode:        "delete",
+				IPAddressId: strconv.Itoa(netboxIPAddress.ID),
+				Address:     netboxIPAddress.Address,
+			})
+		}
+	}
+
+	return results
+}
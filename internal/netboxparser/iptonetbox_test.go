@@ -0,0 +1,92 @@
+package netboxparser
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+const testManagedTagId = 1
+
+const testNetboxInterfaces = `[
+	{"id": 10, "name": "port1"},
+	{"id": 20, "name": "port2"}
+]`
+
+func TestParseIPAddresses(t *testing.T) {
+	tests := []struct {
+		name       string
+		interfaces []model.FortigateInterface
+		netbox     string
+		want       []model.NetboxIPAddressUpdateCreate
+	}{
+		{
+			name:       "create new address",
+			interfaces: []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"192.0.2.1/24"}, Vrf: "RED"}},
+			netbox:     `[]`,
+			want:       []model.NetboxIPAddressUpdateCreate{{Mode: "create", Address: "192.0.2.1/24", InterfaceId: "10", Vrf: "RED"}},
+		},
+		{
+			name:       "unchanged address",
+			interfaces: []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"2001:DB8::1/64"}}},
+			netbox:     `[{"id": 5, "address": "2001:db8::1/64", "assigned_object_id": 10, "tags": [{"id": 1}]}]`,
+		},
+		{
+			name:       "address moved to another interface",
+			interfaces: []model.FortigateInterface{{Name: "port2", IPAddresses: []string{"192.0.2.1/24"}}},
+			netbox:     `[{"id": 5, "address": "192.0.2.1/24", "assigned_object_id": 10}]`,
+			want:       []model.NetboxIPAddressUpdateCreate{{Mode: "update", IPAddressId: "5", Address: "192.0.2.1/24", InterfaceId: "20"}},
+		},
+		{
+			name:       "address moved to a vrf",
+			interfaces: []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"192.0.2.1/24"}, Vrf: "RED"}},
+			netbox:     `[{"id": 5, "address": "192.0.2.1/24", "assigned_object_id": 10, "vrf": {"id": 3, "name": "BLUE"}}]`,
+			want:       []model.NetboxIPAddressUpdateCreate{{Mode: "update", IPAddressId: "5", Address: "192.0.2.1/24", Vrf: "RED"}},
+		},
+		{
+			name:       "stale managed address is deleted",
+			interfaces: []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"192.0.2.1/24"}}},
+			netbox: `[
+				{"id": 5, "address": "192.0.2.1/24", "assigned_object_id": 10, "tags": [{"id": 1}]},
+				{"id": 6, "address": "198.51.100.1/24", "assigned_object_id": 10, "tags": [{"id": 1}]},
+				{"id": 7, "address": "203.0.113.1/24", "assigned_object_id": 20, "tags": [{"id": 2}]},
+				{"id": 8, "address": "10.0.0.1/24", "assigned_object_id": 20}
+			]`,
+			want: []model.NetboxIPAddressUpdateCreate{{Mode: "delete", IPAddressId: "6", Address: "198.51.100.1/24"}},
+		},
+		{
+			name:       "standby address",
+			interfaces: []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"192.0.2.1/24"}, StandbyIPs: []string{"192.0.2.2/24"}}},
+			netbox:     `[{"id": 5, "address": "192.0.2.2/24", "assigned_object_id": 10}]`,
+			want: []model.NetboxIPAddressUpdateCreate{
+				{Mode: "create", Address: "192.0.2.1/24", InterfaceId: "10"},
+				{Mode: "update", IPAddressId: "5", Address: "192.0.2.2/24", Description: "standby"},
+			},
+		},
+		{
+			name:       "same address on two interfaces",
+			interfaces: []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"10.0.0.1/24"}}, {Name: "port2", IPAddresses: []string{"10.0.0.1/24"}}},
+			netbox:     `[{"id": 5, "address": "10.0.0.1/24", "assigned_object_id": 20}]`,
+			want: []model.NetboxIPAddressUpdateCreate{
+				{Mode: "update", IPAddressId: "5", Address: "10.0.0.1/24", InterfaceId: "10"},
+				{Mode: "create", Address: "10.0.0.1/24", InterfaceId: "20"},
+			},
+		},
+		{
+			name:       "interface missing in netbox",
+			interfaces: []model.FortigateInterface{{Name: "port3", IPAddresses: []string{"192.0.2.1/24"}}},
+			netbox:     `[{"id": 5, "address": "192.0.2.1/24", "assigned_object_id": 30, "tags": [{"id": 1}]}]`,
+			want:       []model.NetboxIPAddressUpdateCreate{{Mode: "delete", IPAddressId: "5", Address: "192.0.2.1/24"}},
+		},
+	}
+
+	netboxInterfaces := unmarshalNetbox[[]model.NetboxInterface](t, testNetboxInterfaces)
+	for _, tt := range tests {
+		netboxIPAddresses := unmarshalNetbox[[]model.NetboxIPAddress](t, tt.netbox)
+		got := ParseIPAddresses(&tt.interfaces, &netboxInterfaces, &netboxIPAddresses, testManagedTagId)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
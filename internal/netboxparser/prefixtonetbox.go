@@ -0,0 +1,134 @@
+package netboxparser
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+// interfacePrefix returns the network of an interface address, host addresses like
+// a loopback /32 are not a network and return false.
+func interfacePrefix(address string) (string, bool) {
+	prefix, err := netip.ParsePrefix(address)
+	if err != nil || prefix.IsSingleIP() {
+		return "", false
+	}
+	return prefix.Masked().String(), true
+}
+
+func prefixKey(vrf string, prefix string) string {
+	return strings.ToLower(vrf) + "|" + prefix
+}
+
+func isManagedPrefix(netboxPrefix *model.NetboxPrefix, managedTagId int) bool {
+	for _, tag := range netboxPrefix.Tags {
+		if tag.ID == managedTagId {
+			return true
+		}
+	}
+	return false
+}
+
+// findPrefix returns the prefix in the vrf, a prefix of the site is preferred over a
+// global prefix without site. When the vrf has no such prefix, a managed prefix of the
+// site, or a global one, in a vrf the device does not use for the network is returned
+// so its vrf is moved.
+func findPrefix(netboxPrefixes *[]model.NetboxPrefix, prefix string, vrf string, siteId int, managedTagId int, wanted map[string]bool) *model.NetboxPrefix {
+	var global *model.NetboxPrefix
+	for index, netboxPrefix := range *netboxPrefixes {
+		if netboxPrefix.Prefix != prefix || !strings.EqualFold(netboxPrefix.Vrf.Name, vrf) {
+			continue
+		}
+		if netboxPrefix.Site.ID == siteId {
+			return &(*netboxPrefixes)[index]
+		}
+		if global == nil {
+			global = &(*netboxPrefixes)[index]
+		}
+	}
+	if global != nil {
+		return global
+	}
+
+	for index, netboxPrefix := range *netboxPrefixes {
+		if netboxPrefix.Prefix == prefix && (netboxPrefix.Site.ID == siteId || netboxPrefix.Site.ID == 0) && !wanted[prefixKey(netboxPrefix.Vrf.Name, prefix)] && isManagedPrefix(&netboxPrefix, managedTagId) {
+			return &(*netboxPrefixes)[index]
+		}
+	}
+	return nil
+}
+
+// ParsePrefixes creates a prefix for the network of every interface address. Existing
+// prefixes are only updated when they have the managed tag, the vrf, site, tenant and
+// vlan are kept in sync with the device.
+func ParsePrefixes(deviceInterfaces *[]model.FortigateInterface, netboxPrefixes *[]model.NetboxPrefix, managedTagId int, siteId int, tenantId int) []model.NetboxPrefixUpdateCreate {
+	var results []model.NetboxPrefixUpdateCreate
+	seen := map[string]bool{}
+
+	wanted := map[string]bool{}
+	for _, port := range *deviceInterfaces {
+		for _, address := range port.IPAddresses {
+			if prefix, ok := interfacePrefix(address); ok {
+				wanted[prefixKey(port.Vrf, prefix)] = true
+			}
+		}
+	}
+
+	for _, port := range *deviceInterfaces {
+		var vlanId string
+		if port.InterfaceType == "vlan" {
+			vlanId = port.VlanId
+		}
+
+		for _, address := range port.IPAddresses {
+			prefix, ok := interfacePrefix(address)
+			if !ok || seen[prefixKey(port.Vrf, prefix)] {
+				continue
+			}
+			seen[prefixKey(port.Vrf, prefix)] = true
+
+			existing := findPrefix(netboxPrefixes, prefix, port.Vrf, siteId, managedTagId, wanted)
+			if existing == nil {
+				results = append(results, model.NetboxPrefixUpdateCreate{
+					Mode:     "create",
+					Prefix:   prefix,
+					Vrf:      port.Vrf,
+					VlanId:   vlanId,
+					VlanName: port.Name,
+				})
+				continue
+			}
+			if !isManagedPrefix(existing, managedTagId) {
+				continue
+			}
+
+			update := model.NetboxPrefixUpdateCreate{
+				PrefixId: strconv.Itoa(existing.ID),
+				Prefix:   prefix,
+				Vrf:      port.Vrf,
+				VlanName: port.Name,
+			}
+			if !strings.EqualFold(existing.Vrf.Name, port.Vrf) {
+				update.VrfChanged = true
+			}
+			if existing.Site.ID != siteId {
+				update.SiteId = siteId
+			}
+			if tenantId != 0 && existing.Tenant.ID != tenantId {
+				update.TenantId = tenantId
+			}
+			if vlanId != "" && vlanId != strconv.Itoa(existing.Vlan.Vid) {
+				update.VlanId = vlanId
+			}
+
+			if update.VrfChanged || update.SiteId != 0 || update.TenantId != 0 || update.VlanId != "" {
+				update.Mode = "update"
+				results = append(results, update)
+			}
+		}
+	}
+
+	return results
+}
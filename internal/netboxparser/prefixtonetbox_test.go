@@ -0,0 +1,83 @@
+package netboxparser
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+func TestParsePrefixes(t *testing.T) {
+	const siteId, tenantId = 100, 7
+
+	tests := []struct {
+		name       string
+		interfaces []model.FortigateInterface
+		netbox     string
+		want       []model.NetboxPrefixUpdateCreate
+	}{
+		{
+			name: "create prefixes once per vrf",
+			interfaces: []model.FortigateInterface{
+				{Name: "vlan10", InterfaceType: "vlan", VlanId: "10", IPAddresses: []string{"10.0.10.1/24", "2001:db8:10::1/64"}},
+				{Name: "port2", InterfaceType: "physical", IPAddresses: []string{"10.0.10.2/24"}},
+				{Name: "port3", InterfaceType: "physical", Vrf: "RED", IPAddresses: []string{"10.0.10.1/24"}},
+				{Name: "lo0", InterfaceType: "virtual", IPAddresses: []string{"10.255.0.1/32", "2001:db8::1/128"}},
+			},
+			netbox: `[]`,
+			want: []model.NetboxPrefixUpdateCreate{
+				{Mode: "create", Prefix: "10.0.10.0/24", VlanId: "10", VlanName: "vlan10"},
+				{Mode: "create", Prefix: "2001:db8:10::/64", VlanId: "10", VlanName: "vlan10"},
+				{Mode: "create", Prefix: "10.0.10.0/24", Vrf: "RED", VlanName: "port3"},
+			},
+		},
+		{
+			name:       "unmanaged prefix is left alone",
+			interfaces: []model.FortigateInterface{{Name: "port1", Vrf: "RED", IPAddresses: []string{"192.0.2.1/24"}}},
+			netbox:     `[{"id": 5, "prefix": "192.0.2.0/24", "vrf": {"name": "RED"}, "site": {"id": 200}}]`,
+		},
+		{
+			name:       "managed prefix is moved to the site and tenant",
+			interfaces: []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"192.0.2.1/24"}}},
+			netbox:     `[{"id": 5, "prefix": "192.0.2.0/24", "site": {"id": 200}, "tenant": {"id": 8}, "tags": [{"id": 1}]}]`,
+			want:       []model.NetboxPrefixUpdateCreate{{Mode: "update", PrefixId: "5", Prefix: "192.0.2.0/24", VlanName: "port1", SiteId: siteId, TenantId: tenantId}},
+		},
+		{
+			name:       "prefix of the site is preferred over a global prefix",
+			interfaces: []model.FortigateInterface{{Name: "port1", IPAddresses: []string{"192.0.2.1/24"}}},
+			netbox: `[
+				{"id": 5, "prefix": "192.0.2.0/24", "tags": [{"id": 1}]},
+				{"id": 6, "prefix": "192.0.2.0/24", "site": {"id": 100}, "tenant": {"id": 7}, "tags": [{"id": 1}]}
+			]`,
+		},
+		{
+			name:       "managed prefix follows the vrf of the interface",
+			interfaces: []model.FortigateInterface{{Name: "port1", Vrf: "RED", IPAddresses: []string{"192.0.2.1/24"}}},
+			netbox:     `[{"id": 5, "prefix": "192.0.2.0/24", "vrf": {"name": "BLUE"}, "site": {"id": 100}, "tenant": {"id": 7}, "tags": [{"id": 1}]}]`,
+			want:       []model.NetboxPrefixUpdateCreate{{Mode: "update", PrefixId: "5", Prefix: "192.0.2.0/24", Vrf: "RED", VrfChanged: true, VlanName: "port1"}},
+		},
+		{
+			name: "prefix in a vrf that is still used is not moved",
+			interfaces: []model.FortigateInterface{
+				{Name: "port1", Vrf: "RED", IPAddresses: []string{"192.0.2.1/24"}},
+				{Name: "port2", Vrf: "BLUE", IPAddresses: []string{"192.0.2.1/24"}},
+			},
+			netbox: `[{"id": 5, "prefix": "192.0.2.0/24", "vrf": {"name": "BLUE"}, "site": {"id": 100}, "tenant": {"id": 7}, "tags": [{"id": 1}]}]`,
+			want:   []model.NetboxPrefixUpdateCreate{{Mode: "create", Prefix: "192.0.2.0/24", Vrf: "RED", VlanName: "port1"}},
+		},
+		{
+			name:       "vlan of a managed prefix is updated",
+			interfaces: []model.FortigateInterface{{Name: "vlan20", InterfaceType: "vlan", VlanId: "20", IPAddresses: []string{"10.0.20.1/24"}}},
+			netbox:     `[{"id": 5, "prefix": "10.0.20.0/24", "site": {"id": 100}, "tenant": {"id": 7}, "vlan": {"vid": 21}, "tags": [{"id": 1}]}]`,
+			want:       []model.NetboxPrefixUpdateCreate{{Mode: "update", PrefixId: "5", Prefix: "10.0.20.0/24", VlanId: "20", VlanName: "vlan20"}},
+		},
+	}
+
+	for _, tt := range tests {
+		netboxPrefixes := unmarshalNetbox[[]model.NetboxPrefix](t, tt.netbox)
+		got := ParsePrefixes(&tt.interfaces, &netboxPrefixes, testManagedTagId, siteId, tenantId)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package netboxparser
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+// managementAddresses returns the first ipv4 and ipv6 address of the management
+// interface, or the interface address of the management ip when no interface is given.
+func managementAddresses(deviceInterfaces *[]model.FortigateInterface, managementIP string, managementInterface string) (string, string) {
+	var address4, address6 string
+	nodeAddr, nodeErr := netip.ParseAddr(managementIP)
+
+	for _, port := range *deviceInterfaces {
+		if managementInterface != "" && !strings.EqualFold(port.Name, managementInterface) {
+			continue
+		}
+		for _, address := range port.IPAddresses {
+			prefix, err := netip.ParsePrefix(address)
+			if err != nil || prefix.Addr().IsLinkLocalUnicast() {
+				continue
+			}
+			if managementInterface == "" && (nodeErr != nil || prefix.Addr() != nodeAddr) {
+				continue
+			}
+			if prefix.Addr().Is4() && address4 == "" {
+				address4 = prefix.String()
+			}
+			if prefix.Addr().Is6() && address6 == "" {
+				address6 = prefix.String()
+			}
+		}
+	}
+
+	return address4, address6
+}
+
+func findIPAddressID(netboxIPAddresses *[]model.NetboxIPAddress, address string) int {
+	if address == "" {
+		return 0
+	}
+	for _, netboxIPAddress := range *netboxIPAddresses {
+		if normalizeAddress(netboxIPAddress.Address) == address {
+			return netboxIPAddress.ID
+		}
+	}
+	return 0
+}
+
+// ParsePrimaryIPs returns the ids of the NetBox ip addresses to set as primary ipv4 and
+// ipv6 address of the device, an id is 0 when it is not found or already set.
+func ParsePrimaryIPs(deviceInterfaces *[]model.FortigateInterface, netboxDevice model.NetboxDevice, netboxIPAddresses *[]model.NetboxIPAddress, managementIP string, managementInterface string) (int, int) {
+	address4, address6 := managementAddresses(deviceInterfaces, managementIP, managementInterface)
+
+	primaryIP4 := findIPAddressID(netboxIPAddresses, address4)
+	if primaryIP4 == netboxDevice.PrimaryIP4.ID {
+		primaryIP4 = 0
+	}
+	primaryIP6 := findIPAddressID(netboxIPAddresses, address6)
+	if primaryIP6 == netboxDevice.PrimaryIP6.ID {
+		primaryIP6 = 0
+	}
+
+	return primaryIP4, primaryIP6
+}
@@ -0,0 +1,46 @@
+package netboxparser
+
+import (
+	"testing"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+func TestParsePrimaryIPs(t *testing.T) {
+	interfaces := []model.FortigateInterface{
+		{Name: "port1", IPAddresses: []string{"192.0.2.1/24", "2001:db8::1/64"}},
+		{Name: "mgmt", IPAddresses: []string{"fe80::1/64", "172.16.0.10/24", "2001:DB8:ffff::10/64", "172.16.1.10/24"}},
+		{Name: "lo0", IPAddresses: []string{"10.255.0.1/32"}},
+	}
+	netboxIPAddresses := unmarshalNetbox[[]model.NetboxIPAddress](t, `[
+		{"id": 1, "address": "192.0.2.1/24"},
+		{"id": 2, "address": "2001:db8::1/64"},
+		{"id": 3, "address": "172.16.0.10/24"},
+		{"id": 4, "address": "2001:db8:ffff::10/64"}
+	]`)
+
+	tests := []struct {
+		name                string
+		device              string
+		managementIP        string
+		managementInterface string
+		primaryIP4          int
+		primaryIP6          int
+	}{
+		{"management ip of the node", `{}`, "192.0.2.1", "", 1, 0},
+		{"ipv6 management ip of the node", `{}`, "2001:db8::1", "", 0, 2},
+		{"management interface", `{}`, "192.0.2.1", "MGMT", 3, 4},
+		{"already primary", `{"primary_ip4": {"id": 3}, "primary_ip6": {"id": 2}}`, "192.0.2.1", "mgmt", 0, 4},
+		{"management ip not configured", `{}`, "198.51.100.1", "", 0, 0},
+		{"management ip is a hostname", `{}`, "fw1.example.com", "", 0, 0},
+		{"other interface", `{}`, "", "port1", 1, 2},
+		{"address not in netbox", `{}`, "", "lo0", 0, 0},
+	}
+	for _, tt := range tests {
+		device := unmarshalNetbox[model.NetboxDevice](t, tt.device)
+		primaryIP4, primaryIP6 := ParsePrimaryIPs(&interfaces, device, &netboxIPAddresses, tt.managementIP, tt.managementInterface)
+		if primaryIP4 != tt.primaryIP4 || primaryIP6 != tt.primaryIP6 {
+			t.Errorf("%s: got %d %d, want %d %d", tt.name, primaryIP4, primaryIP6, tt.primaryIP4, tt.primaryIP6)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package netboxparser
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+// tunnelName returns the NetBox name of a tunnel interface, tunnel names are unique
+// so the name of the device is added.
+func tunnelName(deviceName string, interfaceName string) string {
+	return fmt.Sprintf("%s-%s", deviceName, interfaceName)
+}
+
+func tunnelDescription(tunnel *model.Tunnel) string {
+	var result string
+	if tunnel.RemoteGateway != "" {
+		result = fmt.Sprintf("remote-gw: %s", tunnel.RemoteGateway)
+	}
+	return result
+}
+
+// findOutsideIP returns the id of the NetBox ip address of the local gateway.
+func findOutsideIP(netboxIPAddresses *[]model.NetboxIPAddress, localGateway string) string {
+	gateway, err := netip.ParseAddr(localGateway)
+	if err != nil {
+		return ""
+	}
+	for _, netboxIPAddress := range *netboxIPAddresses {
+		prefix, err := netip.ParsePrefix(netboxIPAddress.Address)
+		if err == nil && prefix.Addr() == gateway {
+			return strconv.Itoa(netboxIPAddress.ID)
+		}
+	}
+	return ""
+}
+
+// findPeerInterface returns the ids of the NetBox interface and ip address of the remote
+// gateway, when the address is assigned to an interface in NetBox.
+func findPeerInterface(netboxRemoteIPAddresses *[]model.NetboxIPAddress, remoteGateway string) (string, string) {
+	gateway, err := netip.ParseAddr(remoteGateway)
+	if err != nil {
+		return "", ""
+	}
+	for _, netboxIPAddress := range *netboxRemoteIPAddresses {
+		prefix, err := netip.ParsePrefix(netboxIPAddress.Address)
+		if err == nil && prefix.Addr() == gateway && netboxIPAddress.AssignedObjectType == "dcim.interface" {
+			return strconv.Itoa(netboxIPAddress.AssignedObjectID), strconv.Itoa(netboxIPAddress.ID)
+		}
+	}
+	return "", ""
+}
+
+// TunnelInterfaceIds returns the ids of the NetBox interfaces of the tunnel interfaces
+// of the device.
+func TunnelInterfaceIds(deviceInterfaces *[]model.FortigateInterface, netboxDeviceInterfaces *[]model.NetboxInterface) []string {
+	var result []string
+	for _, port := range *deviceInterfaces {
+		if port.Tunnel == nil {
+			continue
+		}
+		if interfaceId := getParentID(port.Name, netboxDeviceInterfaces); interfaceId != "" {
+			result = append(result, interfaceId)
+		}
+	}
+	return result
+}
+
+// TunnelRemoteGateways returns the remote gateways of the tunnel interfaces of the device.
+func TunnelRemoteGateways(deviceInterfaces *[]model.FortigateInterface) []string {
+	var result []string
+	for _, port := range *deviceInterfaces {
+		if port.Tunnel != nil && port.Tunnel.RemoteGateway != "" && !slices.Contains(result, port.Tunnel.RemoteGateway) {
+			result = append(result, port.Tunnel.RemoteGateway)
+		}
+	}
+	return result
+}
+
+// TunnelPeerInterfaceIds returns the ids of the NetBox interfaces the remote gateways
+// are assigned to.
+func TunnelPeerInterfaceIds(netboxRemoteIPAddresses *[]model.NetboxIPAddress) []string {
+	var result []string
+	for _, netboxIPAddress := range *netboxRemoteIPAddresses {
+		if netboxIPAddress.AssignedObjectType == "dcim.interface" {
+			result = append(result, strconv.Itoa(netboxIPAddress.AssignedObjectID))
+		}
+	}
+	return result
+}
+
+// ParseTunnels creates a tunnel for every tunnel interface of the device and terminates
+// it on the interface, with the local gateway as outside ip. When the remote gateway is
+// assigned to an interface in NetBox the tunnel is terminated on that interface as well,
+// with the remote gateway as outside ip. A tunnel the peer already created is reused.
+func ParseTunnels(deviceInterfaces *[]model.FortigateInterface, deviceName string, netboxDeviceInterfaces *[]model.NetboxInterface, netboxIPAddresses *[]model.NetboxIPAddress, netboxRemoteIPAddresses *[]model.NetboxIPAddress, netboxTunnels *[]model.NetboxTunnel, netboxTerminations *[]model.NetboxTunnelTermination) []model.NetboxTunnelUpdateCreate {
+	var results []model.NetboxTunnelUpdateCreate
+
+	for _, port := range *deviceInterfaces {
+		if port.Tunnel == nil {
+			continue
+		}
+		interfaceId := getParentID(port.Name, netboxDeviceInterfaces)
+		if interfaceId == "" {
+			continue
+		}
+
+		name := tunnelName(deviceName, port.Name)
+		description := tunnelDescription(port.Tunnel)
+		peerInterfaceId, peerOutsideIPId := findPeerInterface(netboxRemoteIPAddresses, port.Tunnel.RemoteGateway)
+		if peerInterfaceId == interfaceId {
+			peerInterfaceId, peerOutsideIPId = "", ""
+		}
+
+		var existing *model.NetboxTunnel
+		for index, netboxTunnel := range *netboxTunnels {
+			if netboxTunnel.Name == name {
+				existing = &(*netboxTunnels)[index]
+				break
+			}
+		}
+		owned := existing != nil
+		if existing == nil {
+			existing = findTerminatedTunnel(netboxTunnels, netboxTerminations, interfaceId)
+		}
+
+		if existing == nil {
+			results = append(results, model.NetboxTunnelUpdateCreate{
+				Mode:            "create",
+				Name:            name,
+				Encapsulation:   port.Tunnel.Encapsulation,
+				Description:     description,
+				InterfaceId:     interfaceId,
+				OutsideIPId:     findOutsideIP(netboxIPAddresses, port.Tunnel.LocalGateway),
+				PeerInterfaceId: peerInterfaceId,
+				PeerOutsideIPId: peerOutsideIPId,
+			})
+			continue
+		}
+
+		update := model.NetboxTunnelUpdateCreate{
+			TunnelId: strconv.Itoa(existing.ID),
+			Name:     existing.Name,
+		}
+		// the tunnel of the peer keeps the encapsulation and description of the peer
+		if owned && existing.Encapsulation.Value != port.Tunnel.Encapsulation {
+			update.Encapsulation = port.Tunnel.Encapsulation
+		}
+		if owned && existing.Description != description {
+			update.Description = description
+		}
+
+		if !isTerminated(netboxTerminations, existing.ID, interfaceId) {
+			update.InterfaceId = interfaceId
+			update.OutsideIPId = findOutsideIP(netboxIPAddresses, port.Tunnel.LocalGateway)
+		}
+		if peerInterfaceId != "" && !isTerminated(netboxTerminations, existing.ID, peerInterfaceId) {
+			update.PeerInterfaceId = peerInterfaceId
+			update.PeerOutsideIPId = peerOutsideIPId
+		}
+
+		if update.Encapsulation != "" || update.Description != "" || update.InterfaceId != "" || update.PeerInterfaceId != "" {
+			update.Mode = "update"
+			results = append(results, update)
+		}
+	}
+
+	return results
+}
+
+func isTerminated(netboxTerminations *[]model.NetboxTunnelTermination, tunnelId int, interfaceId string) bool {
+	for _, termination := range *netboxTerminations {
+		if termination.Tunnel.ID == tunnelId && strconv.Itoa(termination.TerminationID) == interfaceId {
+			return true
+		}
+	}
+	return false
+}
+
+// findTerminatedTunnel returns the tunnel that is already terminated on the interface,
+// like the tunnel the peer created with this device as its remote gateway.
+func findTerminatedTunnel(netboxTunnels *[]model.NetboxTunnel, netboxTerminations *[]model.NetboxTunnelTermination, interfaceId string) *model.NetboxTunnel {
+	for _, termination := range *netboxTerminations {
+		if strconv.Itoa(termination.TerminationID) != interfaceId {
+			continue
+		}
+		for index, netboxTunnel := range *netboxTunnels {
+			if netboxTunnel.ID == termination.Tunnel.ID {
+				return &(*netboxTunnels)[index]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package netboxparser
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mattieserver/netbox-oxidized-sync/internal/model"
+)
+
+func TestParseTunnels(t *testing.T) {
+	netboxInterfaces := unmarshalNetbox[[]model.NetboxInterface](t, testNetboxInterfaces)
+	netboxIPAddresses := unmarshalNetbox[[]model.NetboxIPAddress](t, `[
+		{"id": 100, "address": "203.0.113.2/24", "assigned_object_type": "dcim.interface", "assigned_object_id": 20}
+	]`)
+	netboxRemoteIPAddresses := unmarshalNetbox[[]model.NetboxIPAddress](t, `[
+		{"id": 200, "address": "198.51.100.1/24", "assigned_object_type": "dcim.interface", "assigned_object_id": 50}
+	]`)
+	netboxTunnels := unmarshalNetbox[[]model.NetboxTunnel](t, `[
+		{"id": 1, "name": "fw1-port1", "encapsulation": {"value": "ipsec-tunnel"}, "description": "remote-gw: 198.51.100.1"},
+		{"id": 2, "name": "fw2-vpn", "encapsulation": {"value": "ipsec-tunnel"}, "description": "remote-gw: 203.0.113.2"}
+	]`)
+	tunnel := model.Tunnel{Encapsulation: "ipsec-tunnel", RemoteGateway: "198.51.100.1", LocalGateway: "203.0.113.2"}
+
+	tests := []struct {
+		name         string
+		interfaces   []model.FortigateInterface
+		terminations string
+		want         []model.NetboxTunnelUpdateCreate
+	}{
+		{
+			name:         "create tunnel with peer termination",
+			interfaces:   []model.FortigateInterface{{Name: "port2", Tunnel: &tunnel}},
+			terminations: `[]`,
+			want:         []model.NetboxTunnelUpdateCreate{{Mode: "create", Name: "fw1-port2", Encapsulation: "ipsec-tunnel", Description: "remote-gw: 198.51.100.1", InterfaceId: "20", OutsideIPId: "100", PeerInterfaceId: "50", PeerOutsideIPId: "200"}},
+		},
+		{
+			name:         "create tunnel with unknown peer",
+			interfaces:   []model.FortigateInterface{{Name: "port2", Tunnel: &model.Tunnel{Encapsulation: "gre", RemoteGateway: "192.0.2.1"}}},
+			terminations: `[]`,
+			want:         []model.NetboxTunnelUpdateCreate{{Mode: "create", Name: "fw1-port2", Encapsulation: "gre", Description: "remote-gw: 192.0.2.1", InterfaceId: "20"}},
+		},
+		{
+			name:         "unchanged tunnel",
+			interfaces:   []model.FortigateInterface{{Name: "port1", Tunnel: &tunnel}},
+			terminations: `[{"tunnel": {"id": 1}, "termination_id": 10}, {"tunnel": {"id": 1}, "termination_id": 50}]`,
+		},
+		{
+			name:         "missing terminations",
+			interfaces:   []model.FortigateInterface{{Name: "port1", Tunnel: &model.Tunnel{Encapsulation: "gre", RemoteGateway: "198.51.100.1", LocalGateway: "203.0.113.2"}}},
+			terminations: `[]`,
+			want:         []model.NetboxTunnelUpdateCreate{{Mode: "update", TunnelId: "1", Name: "fw1-port1", Encapsulation: "gre", InterfaceId: "10", OutsideIPId: "100", PeerInterfaceId: "50", PeerOutsideIPId: "200"}},
+		},
+		{
+			name:         "tunnel of the peer is reused",
+			interfaces:   []model.FortigateInterface{{Name: "port2", Tunnel: &tunnel}},
+			terminations: `[{"tunnel": {"id": 2}, "termination_id": 20}, {"tunnel": {"id": 2}, "termination_id": 50}]`,
+		},
+		{
+			name:         "not a tunnel",
+			interfaces:   []model.FortigateInterface{{Name: "port1"}},
+			terminations: `[]`,
+		},
+	}
+
+	for _, tt := range tests {
+		netboxTerminations := unmarshalNetbox[[]model.NetboxTunnelTermination](t, tt.terminations)
+		got := ParseTunnels(&tt.interfaces, "fw1", &netboxInterfaces, &netboxIPAddresses, &netboxRemoteIPAddresses, &netboxTunnels, &netboxTerminations)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTunnelPeerInterfaceIds(t *testing.T) {
+	netboxRemoteIPAddresses := unmarshalNetbox[[]model.NetboxIPAddress](t, `[
+		{"id": 200, "address": "198.51.100.1/24", "assigned_object_type": "dcim.interface", "assigned_object_id": 50},
+		{"id": 201, "address": "192.0.2.1/24"}
+	]`)
+	if got := TunnelPeerInterfaceIds(&netboxRemoteIPAddresses); !slices.Equal(got, []string{"50"}) {
+		t.Errorf("got %v, want [50]", got)
+	}
+}